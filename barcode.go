@@ -0,0 +1,141 @@
+package gompdf
+
+import (
+	"encoding/xml"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/datamatrix"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+	"github.com/mazzegi/gompdf/style"
+	"github.com/pkg/errors"
+)
+
+type BarcodeType string
+
+const (
+	BarcodeCode128    BarcodeType = "code128"
+	BarcodeEAN13      BarcodeType = "ean13"
+	BarcodeDataMatrix BarcodeType = "datamatrix"
+	BarcodeQR         BarcodeType = "qr"
+)
+
+// Barcode renders the value as vector barcode in the foreground color. The size is taken from width and height;
+// a missing one is derived from the other or from the number of modules. If Text is set, the value is written
+// below the code.
+type Barcode struct {
+	Styled
+	XMLName xml.Name    `xml:"barcode"`
+	Type    BarcodeType `xml:"type,attr"`
+	Value   string      `xml:"value,attr"`
+	Text    bool        `xml:"text,attr"`
+}
+
+func (bc *Barcode) encode() (barcode.Barcode, error) {
+	switch bc.Type {
+	case BarcodeCode128:
+		return code128.Encode(bc.Value)
+	case BarcodeEAN13:
+		if len(bc.Value) != 12 && len(bc.Value) != 13 {
+			return nil, errors.Errorf("ean13 value must have 12 or 13 digits")
+		}
+		return ean.Encode(bc.Value)
+	case BarcodeDataMatrix:
+		return datamatrix.Encode(bc.Value)
+	case BarcodeQR:
+		return qr.Encode(bc.Value, qr.M, qr.Auto)
+	default:
+		return nil, errors.Errorf("unknown barcode type (%s)", bc.Type)
+	}
+}
+
+// barcode returns the encoded barcode or nil, if the value can't be encoded.
+func (p *Processor) barcode(bc *Barcode) barcode.Barcode {
+	code, err := bc.encode()
+	if err != nil {
+		p.pdf.SetError(errors.Wrapf(err, "encode barcode (%s)", bc.Value))
+		return nil
+	}
+	return code
+}
+
+// barcodeSize returns the size of the code, without the text. 1D codes default to modules of 1pt and a height
+// of 40pt, 2D codes to modules of 2pt.
+func (p *Processor) barcodeSize(code barcode.Barcode, sty style.Styles) (float64, float64) {
+	bounds := code.Bounds()
+	w, h := sty.Dimension.Width, sty.Dimension.Height
+	if bounds.Dy() == 1 {
+		if w <= 0 {
+			w = p.pdf.PointConvert(float64(bounds.Dx()))
+		}
+		if h <= 0 {
+			h = p.pdf.PointConvert(40)
+		}
+		return w, h
+	}
+	switch {
+	case w <= 0 && h <= 0:
+		w = p.pdf.PointConvert(2 * float64(bounds.Dx()))
+		h = p.pdf.PointConvert(2 * float64(bounds.Dy()))
+	case w <= 0:
+		w = h * float64(bounds.Dx()) / float64(bounds.Dy())
+	case h <= 0:
+		h = w * float64(bounds.Dy()) / float64(bounds.Dx())
+	}
+	return w, h
+}
+
+// barcodeHeight returns the height of the code including the text.
+func (p *Processor) barcodeHeight(bc *Barcode, code barcode.Barcode, sty style.Styles) float64 {
+	_, h := p.barcodeSize(code, sty)
+	if bc.Text {
+		h += p.lineHeight(sty)
+	}
+	return h
+}
+
+func (p *Processor) renderBarcode(bc *Barcode, sty style.Styles) {
+	code := p.barcode(bc)
+	if code == nil {
+		return
+	}
+	w, h := p.barcodeSize(code, sty)
+	p.ensureSpace(sty.Dimension.OffsetY + p.barcodeHeight(bc, code, sty))
+	x0, y0 := p.pdf.GetXY()
+	x0 += sty.Dimension.OffsetX
+	y0 += sty.Dimension.OffsetY
+
+	//dark modules are drawn as rects, joining horizontal runs
+	bounds := code.Bounds()
+	mw := w / float64(bounds.Dx())
+	mh := h / float64(bounds.Dy())
+	p.pdf.SetFillColor(int(sty.Color.Foreground.R), int(sty.Color.Foreground.G), int(sty.Color.Foreground.B))
+	for iy := 0; iy < bounds.Dy(); iy++ {
+		run := 0
+		for ix := 0; ix <= bounds.Dx(); ix++ {
+			if ix < bounds.Dx() && isDark(code, bounds.Min.X+ix, bounds.Min.Y+iy) {
+				run++
+				continue
+			}
+			if run > 0 {
+				p.pdf.Rect(x0+float64(ix-run)*mw, y0+float64(iy)*mh, float64(run)*mw, mh, "F")
+				run = 0
+			}
+		}
+	}
+
+	if bc.Text {
+		p.applyFont(sty.Font)
+		p.pdf.SetTextColor(int(sty.Color.Text.R), int(sty.Color.Text.G), int(sty.Color.Text.B))
+		p.pdf.SetXY(x0, y0+h)
+		p.pdf.CellFormat(w, p.lineHeight(sty), p.encoder(sty.Font)(bc.Value), "", 0, "C", false, 0, "")
+		p.resetStyles()
+	}
+	p.pdf.SetY(y0 + p.barcodeHeight(bc, code, sty))
+}
+
+func isDark(code barcode.Barcode, x, y int) bool {
+	r, g, b, _ := code.At(x, y).RGBA()
+	return r+g+b < 3*0x8000
+}
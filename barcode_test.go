@@ -0,0 +1,26 @@
+package gompdf
+
+import "testing"
+
+func TestEnsureSpaceInCellNearPageBottom(t *testing.T) {
+	//the rows of fixed height fit, their content doesn't
+	for _, content := range []string{
+		`<barcode type="code128" value="12345" style="height: 30"/>`,
+		`<rect width="20" height="30"/>`,
+		`<text bookmark="mark" style="font-point-size: 40">mark</text>`,
+	} {
+		p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<set-y y="195"/>
+<table><tr>
+<td>left</td>
+<td style="height: 6; overflow: hidden">`+content+`</td>
+</tr></table>
+</body>
+</document>`)
+		if n := p.pdf.PageNo(); n != 1 {
+			t.Errorf("%s: want the row on one page, got %d pages", content, n)
+		}
+	}
+}
@@ -15,6 +15,10 @@ func main() {
 	text = "*some* tricky `__ways__` to cut-off your tail\\"
 	mdItems = markdown.NewProcessor().Process(text).WordItems(tr)
 	dumpItems(text, mdItems)
+
+	text = "see [the **docs**](https://example.com/a_b) or [chapter 2](#chapter-2) [not a link"
+	mdItems = markdown.NewProcessor().Process(text).WordItems(tr)
+	dumpItems(text, mdItems)
 }
 
 func dumpItems(src string, items markdown.Items) {
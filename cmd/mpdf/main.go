@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/mazzegi/gompdf"
@@ -11,14 +14,59 @@ import (
 func main() {
 	source := flag.String("source", "../../samples/doc2.xml", "")
 	target := flag.String("target", "doc2.pdf", "")
+	dataFile := flag.String("data", "", "json file used as template data; the source is executed as template if set")
+	check := flag.Bool("check", false, "validate the source and print its diagnostics instead of compiling it")
 	flag.Parse()
 
+	if *check {
+		os.Exit(checkSource(*source))
+	}
+
 	fmt.Printf("compile (%s) to (%s) ...\n", *source, *target)
 	start := time.Now()
-	err := gompdf.ParseAndBuild(*source, *target)
+	var err error
+	if *dataFile != "" {
+		var data interface{}
+		data, err = loadData(*dataFile)
+		if err == nil {
+			err = gompdf.ParseAndBuildTemplate(*source, *target, data, nil)
+		}
+	} else {
+		err = gompdf.ParseAndBuild(*source, *target)
+	}
 	if err != nil {
 		fmt.Printf("compile (%s) to (%s) ...failed: %v\n", *source, *target, err)
 	} else {
 		fmt.Printf("compile (%s) to (%s) ... done in (%s)\n", *source, *target, time.Since(start))
 	}
 }
+
+func loadData(file string) (interface{}, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return nil, fmt.Errorf("decode data (%s): %v", file, err)
+	}
+	return data, nil
+}
+
+// checkSource prints the diagnostics of the source file and returns the exit code.
+func checkSource(file string) int {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		fmt.Printf("check (%s) ...failed: %v\n", file, err)
+		return 2
+	}
+	diags := gompdf.ValidateSource(b)
+	for _, d := range diags {
+		fmt.Printf("%s:%s\n", file, d)
+	}
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -2,33 +2,25 @@ package gompdf
 
 import (
 	"image/color"
-	"strconv"
-	"strings"
+
+	"github.com/mazzegi/gompdf/style"
 )
 
+// RGBAFromHexColor returns the color of the form #rrggbb, or black, if s is malformed. Use ParseHexColor to
+// detect malformed colors.
 func RGBAFromHexColor(s string) color.RGBA {
-	if strings.HasPrefix(s, "#") {
-		s = s[1:]
-	}
-	if len(s) != 6 {
-		return color.RGBA{
-			R: 0,
-			G: 0,
-			B: 0,
-		}
+	cr, err := ParseHexColor(s)
+	if err != nil {
+		return color.RGBA{}
 	}
-	rh := s[0:2]
-	gh := s[2:4]
-	bh := s[4:6]
-
-	ru, _ := strconv.ParseUint(rh, 16, 8)
-	gu, _ := strconv.ParseUint(gh, 16, 8)
-	bu, _ := strconv.ParseUint(bh, 16, 8)
+	return cr
+}
 
-	cr := color.RGBA{
-		R: uint8(ru),
-		G: uint8(gu),
-		B: uint8(bu),
+// ParseHexColor parses a color of the form #rrggbb, where the # is optional, like colors in styles.
+func ParseHexColor(s string) (color.RGBA, error) {
+	rgb, err := style.ParseHexColor(s)
+	if err != nil {
+		return color.RGBA{}, err
 	}
-	return cr
+	return color.RGBA{R: rgb.R, G: rgb.G, B: rgb.B}, nil
 }
@@ -0,0 +1,217 @@
+package gompdf
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+
+	"github.com/mazzegi/gompdf/style"
+	"github.com/pkg/errors"
+)
+
+// Columns flows its instructions down Count columns of equal width, separated by Gap, from the first column to the
+// last and on to the next page. Font, text color, alignment, line height and wrapping of its styles apply to the
+// instructions.
+type Columns struct {
+	Styled
+	XMLName      xml.Name `xml:"columns"`
+	Count        int      `xml:"count,attr"`
+	Gap          float64  `xml:"gap,attr"`
+	Instructions Instructions
+}
+
+func (c *Columns) DecodeAttrs(attrs []xml.Attr) error {
+	err := c.Styled.DecodeAttrs(attrs)
+	if err != nil {
+		return err
+	}
+	c.Count = 1
+	for _, a := range attrs {
+		switch a.Name.Local {
+		case "count":
+			c.Count, err = strconv.Atoi(a.Value)
+			if err != nil || c.Count < 1 {
+				return errors.Errorf("invalid column count (%s)", a.Value)
+			}
+		case "gap":
+			c.Gap, err = strconv.ParseFloat(a.Value, 64)
+			if err != nil || c.Gap < 0 {
+				return errors.Errorf("invalid column gap (%s)", a.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Columns) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return c.Instructions.UnmarshalXML(d, start)
+}
+
+// columnFlow is the state of a columns container being laid out.
+type columnFlow struct {
+	count int
+	//step is the distance between the left edges of adjacent columns
+	step float64
+	col  int
+	//top is where the columns start on the current page, bottom the lowest y reached by a column on it
+	top, bottom float64
+}
+
+// columnShift returns the horizontal offset of the current column to the first one.
+func (p *Processor) columnShift() float64 {
+	if p.columns == nil {
+		return 0
+	}
+	return float64(p.columns.col) * p.columns.step
+}
+
+// shiftColumn moves the frame and the cursor by dx to another column.
+func (p *Processor) shiftColumn(dx float64) {
+	l, _, r, _ := p.pdf.GetMargins()
+	p.pdf.SetLeftMargin(l + dx)
+	p.pdf.SetRightMargin(r - dx)
+	p.pdf.SetX(p.pdf.GetX() + dx)
+}
+
+// nextColumn continues the flow at the top of the next column on the current page. It returns false, if there is
+// none.
+func (p *Processor) nextColumn() bool {
+	cf := p.columns
+	if cf == nil || p.inCell || cf.col >= cf.count-1 {
+		return false
+	}
+	cf.bottom = math.Max(cf.bottom, p.pdf.GetY())
+	x := p.pdf.GetX()
+	cf.col++
+	p.shiftColumn(cf.step)
+	p.pdf.SetXY(x+cf.step, cf.top)
+	return true
+}
+
+// firstColumn moves the frame back to the first column, before a new page starts.
+func (p *Processor) firstColumn() {
+	if p.columns == nil || p.columns.col == 0 {
+		return
+	}
+	p.shiftColumn(-p.columnShift())
+	p.columns.col = 0
+}
+
+// startColumnsPage sets the top of the columns on a new page.
+func (p *Processor) startColumnsPage() {
+	if p.columns == nil {
+		return
+	}
+	p.columns.top = p.pdf.GetY()
+	p.columns.bottom = p.columns.top
+}
+
+// acceptPageBreak is called by fpdf, before it breaks a page automatically. Within columns the flow continues in
+// the next column instead.
+func (p *Processor) acceptPageBreak() bool {
+	auto, _ := p.pdf.GetAutoPageBreak()
+	if !auto || p.inCell {
+		return auto
+	}
+	if p.nextColumn() {
+		return false
+	}
+	p.firstColumn()
+	return true
+}
+
+func (p *Processor) renderColumns(c *Columns, sty style.Styles) {
+	saved := p.currStyles
+	p.currStyles = p.cellFlowStyles(sty)
+	p.resetStyles()
+	defer func() {
+		p.currStyles = saved
+		p.resetStyles()
+	}()
+	if c.Count <= 1 || p.inCell {
+		p.processInstructions(c.Instructions)
+		return
+	}
+
+	l, _, r, _ := p.pdf.GetMargins()
+	pw, _ := p.pdf.GetPageSize()
+	width := (pw - l - r - c.Gap*float64(c.Count-1)) / float64(c.Count)
+	y := p.pdf.GetY()
+	outer := p.columns
+	cf := &columnFlow{count: c.Count, step: width + c.Gap, top: y, bottom: y}
+	p.withFrame(l, width, func() {
+		p.columns = cf
+		p.pdf.SetX(l)
+		p.processInstructions(c.Instructions)
+		//the columns end below their floats
+		p.clearFloats()
+		cf.bottom = math.Max(cf.bottom, p.pdf.GetY())
+		p.firstColumn()
+		p.columns = outer
+	})
+	p.pdf.SetY(cf.bottom)
+}
+
+// columnsHeight returns the height columns take in the flow, when they start at the cursor. The instructions are
+// measured in a single column, which is filled column by column from the cursor to the page bottom like when
+// rendering. Breaks within instructions (e.g. by widows and orphans) aren't taken into account.
+func (p *Processor) columnsHeight(c *Columns, sty style.Styles) float64 {
+	saved := p.currStyles
+	p.currStyles = p.cellFlowStyles(sty)
+	defer func() {
+		p.currStyles = saved
+		p.applyFont(saved.Font)
+	}()
+	if c.Count <= 1 || p.inCell {
+		return p.instructionsHeight(c.Instructions.iss)
+	}
+	l, _, r, _ := p.pdf.GetMargins()
+	pw, _ := p.pdf.GetPageSize()
+	width := (pw - l - r - c.Gap*float64(c.Count-1)) / float64(c.Count)
+	var height float64
+	p.withFrame(l, width, func() {
+		height = p.instructionsHeight(c.Instructions.iss)
+	})
+	bottom, controlled := p.pageBottom()
+	if !controlled {
+		//without page breaks the columns don't break either
+		return height
+	}
+	count := float64(c.Count)
+	avail := math.Max(0, bottom-p.pdf.GetY())
+	if height <= avail {
+		return height
+	}
+	if height <= avail*count {
+		return avail
+	}
+	//the following pages are filled from their top
+	full := bottom - p.pageTopY
+	if full <= 0 {
+		return avail + height
+	}
+	rest := height - avail*count
+	flow := avail
+	for rest > full*count {
+		flow += full
+		rest -= full * count
+	}
+	return flow + math.Min(rest, full)
+}
+
+// columnsWidths returns the widths needed by the columns side by side.
+func (p *Processor) columnsWidths(c *Columns, sty style.Styles) (float64, float64) {
+	saved := p.currStyles
+	p.currStyles = p.cellFlowStyles(sty)
+	defer func() {
+		p.currStyles = saved
+		p.applyFont(saved.Font)
+	}()
+	min, max := p.instructionsWidths(c.Instructions.iss)
+	if p.inCell {
+		return min, max
+	}
+	n := float64(c.Count)
+	gaps := c.Gap * (n - 1)
+	return n*min + gaps, n*max + gaps
+}
@@ -0,0 +1,69 @@
+package gompdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTableInColumns(t *testing.T) {
+	rows := []string{}
+	for i := 0; i < 120; i++ {
+		rows = append(rows, fmt.Sprintf("<tr><td>r%03d</td><td>value</td></tr>", i))
+	}
+	src := `<document>
+<default><unit>mm</unit><format>a4</format><page-breaks>auto</page-breaks>
+<page-margins><left>15</left><top>15</top><right>15</right><bottom>15</bottom></page-margins></default>
+<body><columns count="2" gap="5"><table>` + strings.Join(rows, "") + `</table></columns></body>
+</document>`
+	p := loadTestProcessor(t, src)
+	table := p.doc.Body.iss[0].(*Columns).Instructions.iss[0].(*Table)
+	//rows continue alternately in the first and the second column
+	cols := []float64{15, 15 + (180-5)/2.0 + 5}
+	first := table.Rows[0].Cells[0]
+	col := 0
+	if math.Abs(first.x0-cols[1]) < 0.01 {
+		col = 1
+	}
+	breaks := 0
+	for i, row := range table.Rows {
+		c := row.Cells[0]
+		if i > 0 && c.y0 < table.Rows[i-1].Cells[0].y0 {
+			breaks++
+			col = (col + 1) % 2
+		}
+		if math.Abs(c.x0-cols[col]) > 0.01 {
+			t.Fatalf("row %d: want x0 %v, got %v", i, cols[col], c.x0)
+		}
+	}
+	if breaks < 2 {
+		t.Errorf("want at least 2 column breaks, got %d", breaks)
+	}
+}
+
+func TestColumnsHeight(t *testing.T) {
+	para := strings.Repeat("Lorem ipsum dolor sit amet consectetur. ", 8)
+	src := `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks>
+<page-margins><left>15</left><top>15</top><right>15</right><bottom>15</bottom></page-margins></default>
+<body>
+<columns count="2" gap="5"><text>short</text></columns>
+<columns count="2" gap="5">` + strings.Repeat("<text>"+para+"</text>", 2) + `</columns>
+</body>
+</document>`
+	p := loadTestProcessor(t, src)
+	short := p.doc.Body.iss[0].(*Columns)
+	long := p.doc.Body.iss[1].(*Columns)
+	_, ph := p.pdf.GetPageSize()
+	bottom := ph - 15
+
+	p.pdf.SetY(100)
+	if h, want := p.columnsHeight(short, p.appliedStyles(short)), p.lineHeight(DefaultStyle); math.Abs(h-want) > 0.01 {
+		t.Errorf("short columns: want height %v, got %v", want, h)
+	}
+	//the long columns fill the first column from 100 to the bottom and continue in the second one
+	if h, want := p.columnsHeight(long, p.appliedStyles(long)), bottom-100; math.Abs(h-want) > 0.01 {
+		t.Errorf("long columns: want height %v, got %v", want, h)
+	}
+}
@@ -0,0 +1,137 @@
+package gompdf
+
+import (
+	"reflect"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/mazzegi/gompdf/style"
+	"github.com/pkg/errors"
+)
+
+// Renderer is implemented by custom instructions to render themselves. The styles passed are the current styles
+// with the instruction's class and style applied. Documents are laid out again until their page numbers settle,
+// so Render is called once per layout pass and shouldn't have side effects beyond the pdf.
+type Renderer interface {
+	Render(ctx *Context, sty style.Styles) error
+}
+
+// Measurer is implemented by custom instructions taking space, so that table cells and page break decisions
+// account for them. Height returns the vertical space taken in the given width, Widths the minimal and maximal
+// width needed. Without Measurer a custom instruction is measured as empty.
+type Measurer interface {
+	Height(ctx *Context, sty style.Styles, width float64) float64
+	Widths(ctx *Context, sty style.Styles) (float64, float64)
+}
+
+// RegisterInstruction registers a custom instruction for the XML element named by the XMLName field of the
+// prototype, which must be a pointer to a struct. Like the built-in instructions it is decoded from the document
+// (calling DecodeAttrs with the element's attributes) and styled by embedding Styled or NoStyles. It is rendered,
+// if it implements Renderer (once per layout pass), and measured, if it implements Measurer. Instructions must be
+// registered before loading documents using them.
+func RegisterInstruction(prototype Instruction) error {
+	ty := reflect.TypeOf(prototype)
+	if ty.Kind() != reflect.Ptr || ty.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("register (%T). Instruction must be a pointer to a struct", prototype)
+	}
+	fxml, ok := ty.Elem().FieldByName("XMLName")
+	if !ok {
+		return errors.Errorf("(%T) contains no XMLName", prototype)
+	}
+	name := fxml.Tag.Get("xml")
+	if name == "" {
+		return errors.Errorf("(%T) has no xml name", prototype)
+	}
+	if _, ok := instructionRegistry.types[name]; ok || structureElements[name] != nil || instructionContainers[name] {
+		return errors.Errorf("register (%T): element (%s) is already defined", prototype, name)
+	}
+	return instructionRegistry.Register(prototype)
+}
+
+// Context gives custom instructions access to the layout of the document.
+type Context struct {
+	p *Processor
+}
+
+// PDF returns the underlying pdf. Position, font and colors may be changed; font and colors are reset after
+// rendering.
+func (c *Context) PDF() *gofpdf.Fpdf {
+	return c.p.pdf
+}
+
+// Width returns width, if set (> 0), or the width available in the current frame.
+func (c *Context) Width(width float64) float64 {
+	return c.p.effectiveWidth(width)
+}
+
+// EnsureSpace starts a new page, if height doesn't fit on the current page anymore.
+func (c *Context) EnsureSpace(height float64) {
+	c.p.ensureSpace(height)
+}
+
+// LineHeight returns the height of a line of text in the styles' font.
+func (c *Context) LineHeight(sty style.Styles) float64 {
+	return c.p.lineHeight(sty)
+}
+
+// Write writes the (markdown) text at the current position like a text instruction, wrapped to width.
+func (c *Context) Write(text string, width float64, sty style.Styles) {
+	c.p.write(text, width, sty)
+}
+
+// TextHeight returns the height of the (markdown) text wrapped to width.
+func (c *Context) TextHeight(text string, width float64, sty style.Styles) float64 {
+	return c.p.textHeight(text, width, sty)
+}
+
+// TextWidths returns the width of the widest word and the width of the whole (markdown) text.
+func (c *Context) TextWidths(text string, sty style.Styles) (float64, float64) {
+	return c.p.textWidths(text, sty.Font)
+}
+
+// Replace replaces the placeholders (e.g. {cp} or {date}) in s.
+func (c *Context) Replace(s string) string {
+	return c.p.replacePlaceholders(s)
+}
+
+func (p *Processor) renderCustom(i Instruction, sty style.Styles) {
+	r, ok := i.(Renderer)
+	if !ok {
+		return
+	}
+	p.setStyles(sty)
+	err := r.Render(&Context{p: p}, sty)
+	p.resetStyles()
+	if err != nil {
+		p.pdf.SetError(errors.Wrapf(err, "render (%T)", i))
+	}
+}
+
+func (p *Processor) customHeight(i Instruction) float64 {
+	m, ok := i.(Measurer)
+	if !ok {
+		return 0
+	}
+	sty := p.appliedStyles(i)
+	p.setStyles(sty)
+	defer p.resetStyles()
+	return m.Height(&Context{p: p}, sty, p.effectiveWidth(sty.Dimension.Width))
+}
+
+func (p *Processor) customWidths(i Instruction) (float64, float64) {
+	m, ok := i.(Measurer)
+	if !ok {
+		return 0, 0
+	}
+	sty := p.appliedStyles(i)
+	p.setStyles(sty)
+	defer p.resetStyles()
+	return m.Widths(&Context{p: p}, sty)
+}
+
+// setStyles applies font and colors of sty to the pdf.
+func (p *Processor) setStyles(sty style.Styles) {
+	p.applyFont(sty.Font)
+	p.pdf.SetTextColor(int(sty.Color.Text.R), int(sty.Color.Text.G), int(sty.Color.Text.B))
+	p.pdf.SetDrawColor(int(sty.Color.Foreground.R), int(sty.Color.Foreground.G), int(sty.Color.Foreground.B))
+	p.pdf.SetFillColor(int(sty.Color.Background.R), int(sty.Color.Background.G), int(sty.Color.Background.B))
+}
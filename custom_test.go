@@ -0,0 +1,134 @@
+package gompdf
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+// testBadge writes its text in a box of fixed height.
+type testBadge struct {
+	Styled
+	XMLName xml.Name `xml:"test-badge"`
+	Text    string   `xml:"text,attr"`
+}
+
+const testBadgeHeight = 25
+
+func (b *testBadge) Render(ctx *Context, sty style.Styles) error {
+	pdf := ctx.PDF()
+	ctx.EnsureSpace(testBadgeHeight)
+	x, y := pdf.GetXY()
+	pdf.Rect(x, y, ctx.Width(sty.Dimension.Width), testBadgeHeight, "D")
+	ctx.Write(ctx.Replace(b.Text), ctx.Width(sty.Dimension.Width), sty)
+	pdf.SetXY(x, y+testBadgeHeight)
+	return nil
+}
+
+func (b *testBadge) Height(ctx *Context, sty style.Styles, width float64) float64 {
+	return testBadgeHeight
+}
+
+func (b *testBadge) Widths(ctx *Context, sty style.Styles) (float64, float64) {
+	return ctx.TextWidths(b.Text, sty)
+}
+
+var registerTestBadge sync.Once
+
+func TestCustomInstruction(t *testing.T) {
+	registerTestBadge.Do(func() {
+		err := RegisterInstruction(&testBadge{})
+		if err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	})
+	src := `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<test-badge text="body badge on page {cp}"/>
+<table><tr><td><test-badge text="cell badge"/></td><td>cell text</td></tr></table>
+</body>
+</document>`
+	p := loadTestProcessor(t, src)
+	badge, ok := p.doc.Body.iss[0].(*testBadge)
+	if !ok {
+		t.Fatalf("want the custom instruction decoded, got %T", p.doc.Body.iss[0])
+	}
+	if badge.Text != "body badge on page {cp}" {
+		t.Errorf("want its attributes decoded, got (%s)", badge.Text)
+	}
+	if h := p.instructionHeight(badge); h != testBadgeHeight {
+		t.Errorf("want the measured height %v, got %v", testBadgeHeight, h)
+	}
+	tab := p.doc.Body.iss[1].(*Table)
+	if h := p.tableHeight(tab, p.appliedStyles(tab)); h < testBadgeHeight {
+		t.Errorf("want the cell as high as the badge, got table height %v", h)
+	}
+	cell := tab.Rows[0].Cells[0]
+	if math.Abs(cell.y1-cell.y0-testBadgeHeight) > 0.01 {
+		t.Errorf("want the rendered cell as high as the badge, got %v", cell.y1-cell.y0)
+	}
+
+	content := pdfContent(t, processTestSource(t, src))
+	//text is written word by word, the page placeholder replaced
+	for _, s := range []string{"(body )", "(page )", "(1)Tj", "(badge)Tj", "(cell )", "(text)Tj"} {
+		if !strings.Contains(content, s) {
+			t.Errorf("want %s rendered", s)
+		}
+	}
+	if strings.Count(content, " re S") < 2 {
+		t.Errorf("want the badges' boxes drawn")
+	}
+}
+
+type testNoXMLName struct {
+	NoStyles
+}
+
+type testDuplicate struct {
+	NoStyles
+	XMLName xml.Name `xml:"text"`
+}
+
+type testReserved struct {
+	NoStyles
+	XMLName xml.Name `xml:"body"`
+}
+
+type testReservedStructure struct {
+	NoStyles
+	XMLName xml.Name `xml:"page-margins"`
+}
+
+func TestRegisterInstructionErrors(t *testing.T) {
+	tests := []struct {
+		prototype Instruction
+		err       string
+	}{
+		{prototype: &testNoXMLName{}, err: "contains no XMLName"},
+		{prototype: &testDuplicate{}, err: "element (text) is already defined"},
+		{prototype: &testReserved{}, err: "element (body) is already defined"},
+		{prototype: &testReservedStructure{}, err: "element (page-margins) is already defined"},
+	}
+	for _, test := range tests {
+		err := RegisterInstruction(test.prototype)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("(%T): want error %q, got %v", test.prototype, test.err, err)
+		}
+	}
+
+	registerTestBadge.Do(func() {
+		err := RegisterInstruction(&testBadge{})
+		if err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	})
+	err := RegisterInstruction(&testBadge{})
+	if err == nil || !strings.Contains(err.Error(), "element (test-badge) is already defined") {
+		t.Errorf("want error for registering an instruction twice, got %v", err)
+	}
+}
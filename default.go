@@ -16,6 +16,7 @@ var DefaultStyle = style.Styles{
 		Border:  style.Border{Left: 0, Top: 0, Right: 0, Bottom: 0},
 		Padding: style.Padding{Left: 0, Top: 0, Right: 0, Bottom: 0},
 		Margin:  style.Margin{Left: 0, Top: 0, Right: 0, Bottom: 0},
+		Float:   style.FloatNone,
 	},
 	Dimension: style.Dimension{
 		Width:      -1,
@@ -25,6 +26,7 @@ var DefaultStyle = style.Styles{
 		OffsetY:    0,
 	},
 	Table: style.Table{
+		Layout:      style.TableLayoutFixed,
 		ColumnWidth: -1,
 		ColumnSpan:  1,
 	},
@@ -36,5 +38,18 @@ var DefaultStyle = style.Styles{
 		Foreground: style.Black,
 		Text:       style.Black,
 		Background: style.White,
+		Link:       style.Blue,
+	},
+	Wrap: style.Wrap{
+		Hyphenate:    style.HyphenateNone,
+		WordBreak:    style.WordBreakNormal,
+		OverflowWrap: style.OverflowWrapAnywhere,
+		Overflow:     style.OverflowVisible,
+	},
+	Paging: style.Paging{
+		PageBreakInside: style.PageBreakInsideAuto,
+		KeepWithNext:    false,
+		Widows:          1,
+		Orphans:         1,
 	},
 }
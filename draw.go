@@ -1,35 +1,146 @@
 package gompdf
 
-import "github.com/mazzegi/gompdf/style"
+import (
+	"math"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+type borderSide struct {
+	drawn bool
+	width float64
+	color style.RGB
+	line  style.BorderLineStyle
+}
+
+// borderSides returns the left, top, right and bottom border sides of a box.
+func borderSides(sty style.Styles) [4]borderSide {
+	b := sty.Box
+	flags := [4]int{b.Border.Left, b.Border.Top, b.Border.Right, b.Border.Bottom}
+	widths := [4]float64{b.BorderWidth.Left, b.BorderWidth.Top, b.BorderWidth.Right, b.BorderWidth.Bottom}
+	colors := [4]style.RGB{b.BorderColor.Left, b.BorderColor.Top, b.BorderColor.Right, b.BorderColor.Bottom}
+	lines := [4]style.BorderLineStyle{b.BorderStyle.Left, b.BorderStyle.Top, b.BorderStyle.Right, b.BorderStyle.Bottom}
+	var sides [4]borderSide
+	for i := range sides {
+		sides[i] = borderSide{
+			drawn: flags[i] > 0,
+			width: sty.Draw.LineWidth,
+			color: sty.Color.Foreground,
+			line:  lines[i],
+		}
+		if b.BorderWidth.Set {
+			sides[i].width = widths[i]
+		}
+		if b.BorderColor.Set {
+			sides[i].color = colors[i]
+		}
+	}
+	return sides
+}
+
+// corner is the center and radius of a rounded box corner. Sharp corners have radius 0.
+type corner struct {
+	cx, cy, r float64
+}
+
+// boxCorners returns the top-left, top-right, bottom-right and bottom-left corners of a box. The radii are limited
+// to half the box's width and height.
+func boxCorners(x0, y0, x1, y1 float64, sty style.Styles) [4]corner {
+	br := sty.Box.BorderRadius
+	max := math.Min(x1-x0, y1-y0) / 2
+	r := func(v float64) float64 {
+		return math.Max(0, math.Min(v, max))
+	}
+	tl, tr, brr, bl := r(br.TopLeft), r(br.TopRight), r(br.BottomRight), r(br.BottomLeft)
+	return [4]corner{
+		{cx: x0 + tl, cy: y0 + tl, r: tl},
+		{cx: x1 - tr, cy: y0 + tr, r: tr},
+		{cx: x1 - brr, cy: y1 - brr, r: brr},
+		{cx: x0 + bl, cy: y1 - bl, r: bl},
+	}
+}
 
 func (p *Processor) drawBox(x0, y0, x1, y1 float64, sty style.Styles) {
-	p.pdf.SetLineWidth(sty.Draw.LineWidth)
-	p.pdf.SetDrawColor(int(sty.Color.Foreground.R), int(sty.Color.Foreground.G), int(sty.Color.Foreground.B))
+	corners := boxCorners(x0, y0, x1, y1, sty)
+	shape := func(dx, dy float64) {
+		p.boxPath(corners, dx, dy)
+		p.pdf.DrawPath("F")
+	}
+	if shadow := sty.Box.BoxShadow; shadow.Set {
+		p.pdf.SetFillColor(int(shadow.Color.R), int(shadow.Color.G), int(shadow.Color.B))
+		shape(shadow.OffsetX, shadow.OffsetY)
+	}
 	p.pdf.SetFillColor(int(sty.Color.Background.R), int(sty.Color.Background.G), int(sty.Color.Background.B))
+	shape(0, 0)
 
-	width := x1 - x0
-	height := y1 - y0
-	p.pdf.Rect(x0, y0, width, height, "F")
-	p.pdf.MoveTo(x0-sty.Draw.LineWidth/2, y0)
-	if sty.Box.Border.Top > 0 {
-		p.pdf.LineTo(x0+width, y0)
-	} else {
-		p.pdf.MoveTo(x0+width, y0)
+	//the sides are drawn separately, each from the middle of its first corner to the middle of its second corner.
+	//At sharp corners the sides are extended by half the width of the adjacent sides to close the corners.
+	sides := borderSides(sty)
+	left, top, right, bottom := sides[0], sides[1], sides[2], sides[3]
+	tl, tr, br, bl := corners[0], corners[1], corners[2], corners[3]
+	ext := func(s borderSide) float64 {
+		if !s.drawn {
+			return 0
+		}
+		return s.width / 2
 	}
-	if sty.Box.Border.Right > 0 {
-		p.pdf.LineTo(x0+width, y1)
-	} else {
-		p.pdf.MoveTo(x0+width, y1)
+	p.drawBorderSide(top, 90, tl, x0-ext(left), y0, tr, x1+ext(right), y0)
+	p.drawBorderSide(right, 0, tr, x1, y0-ext(top), br, x1, y1+ext(bottom))
+	p.drawBorderSide(bottom, 270, br, x1+ext(right), y1, bl, x0-ext(left), y1)
+	p.drawBorderSide(left, 180, bl, x0, y1+ext(bottom), tl, x0, y0-ext(top))
+	p.pdf.SetDashPattern([]float64{}, 0)
+}
+
+// boxPath adds the outline of a box with the corners, offset by dx, dy, to the current path. It runs clockwise from
+// the top of the top-left corner. (fpdf's RoundedRect leaves an unbalanced graphics state.)
+func (p *Processor) boxPath(corners [4]corner, dx, dy float64) {
+	//the corner arcs run from the start angles to 90 degrees less
+	starts := [4]float64{180, 90, 0, 270}
+	tl := corners[0]
+	p.pdf.MoveTo(tl.cx+dx, tl.cy-tl.r+dy)
+	for _, i := range []int{1, 2, 3, 0} {
+		c := corners[i]
+		if c.r == 0 {
+			p.pdf.LineTo(c.cx+dx, c.cy+dy)
+			continue
+		}
+		p.pdf.ArcTo(c.cx+dx, c.cy+dy, c.r, c.r, 0, starts[i], starts[i]-90)
+	}
+	p.pdf.ClosePath()
+}
+
+// drawBorderSide draws the side facing the direction deg (counter-clockwise from 3 o'clock) from corner ca to
+// corner cb. At sharp corners it starts at xa, ya and ends at xb, yb.
+func (p *Processor) drawBorderSide(s borderSide, deg float64, ca corner, xa, ya float64, cb corner, xb, yb float64) {
+	if !s.drawn {
+		return
+	}
+	p.pdf.SetLineWidth(s.width)
+	p.pdf.SetDrawColor(int(s.color.R), int(s.color.G), int(s.color.B))
+	//dashes scale with the line width, but stay visible for hairlines
+	unit := s.width
+	if min := p.pdf.PointConvert(0.5); unit < min {
+		unit = min
+	}
+	switch s.line {
+	case style.BorderLineDashed:
+		p.pdf.SetDashPattern([]float64{3 * unit, 2 * unit}, 0)
+	case style.BorderLineDotted:
+		p.pdf.SetDashPattern([]float64{unit, unit}, 0)
+	default:
+		p.pdf.SetDashPattern([]float64{}, 0)
 	}
-	if sty.Box.Border.Bottom > 0 {
-		p.pdf.LineTo(x0, y1)
+	if ca.r > 0 {
+		rad := (deg + 45) * math.Pi / 180
+		p.pdf.MoveTo(ca.cx+ca.r*math.Cos(rad), ca.cy-ca.r*math.Sin(rad))
+		p.pdf.ArcTo(ca.cx, ca.cy, ca.r, ca.r, 0, deg+45, deg)
 	} else {
-		p.pdf.MoveTo(x0, y1)
+		p.pdf.MoveTo(xa, ya)
 	}
-	if sty.Box.Border.Left > 0 {
-		p.pdf.LineTo(x0, y0)
+	if cb.r > 0 {
+		p.pdf.ArcTo(cb.cx, cb.cy, cb.r, cb.r, 0, deg, deg-45)
 	} else {
-		p.pdf.MoveTo(x0, y0)
+		p.pdf.LineTo(xb, yb)
 	}
 	p.pdf.DrawPath("D")
 }
@@ -0,0 +1,89 @@
+package gompdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+func TestBorderSides(t *testing.T) {
+	red, blue := style.RGB{R: 255}, style.RGB{B: 255}
+	sty := DefaultStyle
+	sty.Draw.LineWidth = 0.3
+	sty.Color.Foreground = blue
+	sty.Box.Border = style.Border{Left: 1, Top: 0, Right: 1, Bottom: 1}
+	sty.Box.BorderStyle = style.BorderStyle{Left: style.BorderLineDashed, Bottom: style.BorderLineDotted}
+
+	//without border widths and colors the sides are drawn with line-width and color
+	sides := borderSides(sty)
+	want := [4]borderSide{
+		{drawn: true, width: 0.3, color: blue, line: style.BorderLineDashed},
+		{drawn: false, width: 0.3, color: blue},
+		{drawn: true, width: 0.3, color: blue},
+		{drawn: true, width: 0.3, color: blue, line: style.BorderLineDotted},
+	}
+	if sides != want {
+		t.Errorf("want sides %v, got %v", want, sides)
+	}
+
+	sty.Box.BorderWidth = style.BorderWidth{Set: true, Left: 1, Top: 2, Right: 3, Bottom: 4}
+	sty.Box.BorderColor = style.BorderColor{Set: true, Left: red, Top: blue, Right: red, Bottom: blue}
+	sides = borderSides(sty)
+	for i, w := range []float64{1, 2, 3, 4} {
+		if sides[i].width != w {
+			t.Errorf("side %d: want width %v, got %v", i, w, sides[i].width)
+		}
+	}
+	if sides[0].color != red || sides[1].color != blue {
+		t.Errorf("want the colors of the sides, got %v and %v", sides[0].color, sides[1].color)
+	}
+}
+
+func TestDrawBorderStyles(t *testing.T) {
+	content := pdfContent(t, processTestSource(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<box style="border: 1,1,1,1; border-width: 1; border-style: dashed,dotted,solid,solid">dashes</box>
+</body>
+</document>`))
+	//dashes and dots scale with the line width of 1mm (2.83pt)
+	for _, pattern := range []string{"[8.50 5.67] 0.00 d", "[2.83 2.83] 0.00 d", "[] 0.00 d"} {
+		if !strings.Contains(content, pattern) {
+			t.Errorf("want dash pattern (%s) set", pattern)
+		}
+	}
+}
+
+func TestBoxCorners(t *testing.T) {
+	sty := DefaultStyle
+	sty.Box.BorderRadius = style.BorderRadius{TopLeft: 2, TopRight: 0, BottomRight: 50, BottomLeft: -1}
+	//the radii are limited to half the width or height and negative radii are sharp
+	want := [4]corner{
+		{cx: 12, cy: 22, r: 2},
+		{cx: 40, cy: 20, r: 0},
+		{cx: 35, cy: 25, r: 5},
+		{cx: 10, cy: 30, r: 0},
+	}
+	if corners := boxCorners(10, 20, 40, 30, sty); corners != want {
+		t.Errorf("want corners %v, got %v", want, corners)
+	}
+}
+
+func TestDrawRoundedBoxWithShadow(t *testing.T) {
+	content := pdfContent(t, processTestSource(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<box style="border: 1,1,1,1; border-radius: 3; box-shadow: 1,1,#102030; background-color: #ffffff">rounded</box>
+</body>
+</document>`))
+	//the shadow is filled before the box's background
+	shadow, background := strings.Index(content, "0.063 0.125 0.188 rg"), strings.Index(content, "1.000 g")
+	if shadow < 0 || background < shadow {
+		t.Errorf("want the shadow filled before the background, got %d and %d", shadow, background)
+	}
+	//the rounded corners are drawn as curves
+	if n := strings.Count(content, " c\n"); n < 8 {
+		t.Errorf("want curves for the corners of box and border, got %d", n)
+	}
+}
@@ -0,0 +1,140 @@
+package gompdf
+
+import (
+	"math"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/mazzegi/gompdf/style"
+)
+
+// floatArea is the area a floating image takes on a page, including its margins.
+type floatArea struct {
+	page           int
+	side           style.Float
+	x0, y0, x1, y1 float64
+}
+
+// floating returns true, if the instruction is an image floating at the left or right. In table cells images don't
+// float.
+func (p *Processor) floating(i Instruction, sty style.Styles) bool {
+	if _, ok := i.(*Image); !ok || p.inCell {
+		return false
+	}
+	return sty.Box.Float == style.FloatLeft || sty.Box.Float == style.FloatRight
+}
+
+// clearsFloats returns true for the blocks, which start below the floats instead of wrapping around them.
+func (p *Processor) clearsFloats(i Instruction) bool {
+	switch i := i.(type) {
+	case *Box, *Table, *Barcode, *Columns:
+		return true
+	case *Image:
+		return !p.floating(i, p.appliedStyles(i))
+	case shape:
+		return i.shape().Position != ShapePositionAbsolute
+	default:
+		return false
+	}
+}
+
+// floatEdges returns the edges of the horizontal range [left, right] narrowed by the floats of the current page
+// within the vertical range [y0, y1].
+func (p *Processor) floatEdges(left, right, y0, y1 float64) (float64, float64) {
+	page := p.pdf.PageNo()
+	for _, f := range p.floats {
+		if f.page != page || f.y0 >= y1 || f.y1 <= y0 || f.x0 >= right || f.x1 <= left {
+			continue
+		}
+		switch f.side {
+		case style.FloatLeft:
+			left = math.Max(left, f.x1)
+		case style.FloatRight:
+			right = math.Min(right, f.x0)
+		}
+	}
+	return left, right
+}
+
+// floatSpans returns the spans (see wrapLines) of text lines written at the cursor in the range [x, x+width], which
+// wrap around the floats. Lines continuing on the next page, from pageBreak on, if it isn't negative, don't wrap.
+func (p *Processor) floatSpans(x, width, lineHeight float64, pageBreak int) func(n int) (float64, float64, bool) {
+	if len(p.floats) == 0 {
+		return func(int) (float64, float64, bool) { return 0, width, false }
+	}
+	y := p.pdf.GetY()
+	bottom, controlled := p.pageBottom()
+	return func(n int) (float64, float64, bool) {
+		y0 := y + float64(n)*lineHeight
+		if (controlled && y0+lineHeight > bottom) || (pageBreak >= 0 && n >= pageBreak) {
+			return 0, width, false
+		}
+		l, r := p.floatEdges(x, x+width, y0, y0+lineHeight)
+		return l - x, math.Max(0, r-l), l > x || r < x+width
+	}
+}
+
+// clearFloats moves the cursor below the floats of the current page beside the frame and drops all floats.
+func (p *Processor) clearFloats() {
+	if len(p.floats) == 0 {
+		return
+	}
+	l, _, r, _ := p.pdf.GetMargins()
+	pw, _ := p.pdf.GetPageSize()
+	y := p.pdf.GetY()
+	bottom := y
+	for _, f := range p.floats {
+		if f.page == p.pdf.PageNo() && f.x0 < pw-r && f.x1 > l {
+			bottom = math.Max(bottom, f.y1)
+		}
+	}
+	p.floats = nil
+	if bottom > y {
+		p.pdf.SetY(bottom)
+	}
+}
+
+// floatHeight returns the height a floating image takes below the cursor, including its margins. It is 0 for other
+// instructions.
+func (p *Processor) floatHeight(i Instruction) float64 {
+	img, ok := i.(*Image)
+	if !ok {
+		return 0
+	}
+	sty := p.appliedStyles(i)
+	if !p.floating(i, sty) {
+		return 0
+	}
+	info := p.registerImage(img.Source)
+	if info == nil {
+		return 0
+	}
+	_, h := p.imageSize(info, sty)
+	return sty.Box.Margin.Top + sty.Dimension.OffsetY + h + sty.Box.Margin.Bottom
+}
+
+// renderFloatImage draws an image at the left or right of the frame, beside earlier floats, and leaves the cursor,
+// so that the following text wraps around it.
+func (p *Processor) renderFloatImage(img *Image, sty style.Styles) {
+	info := p.registerImage(img.Source)
+	if info == nil {
+		return
+	}
+	w, h := p.imageSize(info, sty)
+	m := sty.Box.Margin
+	p.ensureSpace(p.floatHeight(img))
+
+	l, _, r, _ := p.pdf.GetMargins()
+	pw, _ := p.pdf.GetPageSize()
+	y0 := p.pdf.GetY()
+	f := floatArea{page: p.pdf.PageNo(), side: sty.Box.Float, y0: y0, y1: y0 + p.floatHeight(img)}
+	left, right := p.floatEdges(l, pw-r, f.y0, f.y1)
+	if f.side == style.FloatRight {
+		f.x0, f.x1 = right-m.Left-w-m.Right, right
+	} else {
+		f.x0, f.x1 = left, left+m.Left+w+m.Right
+	}
+	x := f.x0 + m.Left + sty.Dimension.OffsetX
+	y := y0 + m.Top + sty.Dimension.OffsetY
+	p.pdf.ImageOptions(img.Source, x, y, w, h, false, gofpdf.ImageOptions{}, 0, "")
+	p.floats = append(p.floats, f)
+}
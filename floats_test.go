@@ -0,0 +1,51 @@
+package gompdf
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	err := png.Encode(buf, image.NewGray(image.Rect(0, 0, 2, 2)))
+	if err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestInstructionsHeightWithFloat(t *testing.T) {
+	src := `<document>
+<default><unit>mm</unit><format>a4</format><page-breaks>auto</page-breaks></default>
+<body>
+<image style="float: left; width: 20; height: 30; margin: 0,0,3,2">img</image>
+<text>beside the float</text>
+<text style="width: 10">box</text>
+<image style="width: 10; height: 10">img</image>
+</body>
+</document>`
+	p := loadTestProcessor(t, src, WithImage("img", bytes.NewReader(testPNG(t))))
+	is := p.doc.Body.iss
+	lineHeight := p.lineHeight(DefaultStyle)
+	tests := []struct {
+		count int
+		want  float64
+	}{
+		//the float is taller than the text beside it
+		{count: 3, want: 32},
+		//the block image starts below the float
+		{count: 4, want: 32 + 10},
+	}
+	for _, test := range tests {
+		if h := p.instructionsHeight(is[:test.count]); math.Abs(h-test.want) > 0.01 {
+			t.Errorf("%d instructions: want height %v, got %v", test.count, test.want, h)
+		}
+	}
+	if h := p.instructionsHeight(is[1:2]); math.Abs(h-lineHeight) > 0.01 {
+		t.Errorf("text: want height %v, got %v", lineHeight, h)
+	}
+}
@@ -0,0 +1,80 @@
+package gompdf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestNewProcessorReadsFonts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gompdf")
+	if err != nil {
+		t.Fatalf("temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "font.ttf"), []byte("font data"), 0644)
+	if err != nil {
+		t.Fatalf("write font: %v", err)
+	}
+	doc, err := Load(strings.NewReader(`<document><fonts><font family="doc" file="font.ttf"/></fonts><body/></document>`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := NewProcessor(doc, WithFontDir(dir), WithFont("option", "B", "font.ttf"))
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	if len(p.fonts) != 2 || p.fonts[0].family != "doc" || p.fonts[1].family != "option" {
+		t.Fatalf("want the document's font before the option's, got %v", p.fonts)
+	}
+	for _, reg := range p.fonts {
+		if string(reg.data) != "font data" {
+			t.Errorf("font (%s): want its data read, got %q", reg.family, reg.data)
+		}
+	}
+
+	_, err = NewProcessor(doc, WithFontDir(dir), WithFont("missing", "", "missing.ttf"))
+	if err == nil {
+		t.Errorf("want error for a missing font file")
+	}
+}
+
+// utf16BE returns s encoded like fpdf writes text in UTF-8 fonts.
+func utf16BE(s string) string {
+	b := []byte{}
+	for _, r := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(r>>8), byte(r))
+	}
+	return string(b)
+}
+
+func TestUTF8FontText(t *testing.T) {
+	b := processTestSource(t, `<document>
+<fonts><font family="dejavu" file="DejaVuSansCondensed.ttf"/></fonts>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<text bookmark="Ωμέγα Жук" style="font-family: dejavu">Ωμέγα Жук {cp}/{np}</text>
+<page-break/>
+<text style="font-family: dejavu">Ζ {cp}/{np}</text>
+</body>
+</document>`, WithFontDir("testdata"))
+	if !bytes.Contains(b, []byte("/FontFile2")) || !bytes.Contains(b, []byte("/ToUnicode")) {
+		t.Errorf("want the font embedded with a unicode mapping")
+	}
+	content := pdfContent(t, b)
+	for _, s := range []string{"Ωμέγα", "Жук", "1/2", "Ζ", "2/2"} {
+		if !strings.Contains(content, utf16BE(s)) {
+			t.Errorf("want (%s) written in the content", s)
+		}
+	}
+	if strings.Contains(content, "{np}") || strings.Contains(content, utf16BE("{np}")) {
+		t.Errorf("want the page count alias replaced")
+	}
+	if !bytes.Contains(b, []byte("/Title (\xfe\xff"+utf16BE("Ωμέγα Жук")+")")) {
+		t.Errorf("want the bookmark title in UTF-16")
+	}
+}
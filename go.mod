@@ -3,6 +3,7 @@ module github.com/mazzegi/gompdf
 go 1.13
 
 require (
+	github.com/boombuler/barcode v1.1.0
 	github.com/fsnotify/fsnotify v1.4.9
 	github.com/jung-kurt/gofpdf/v2 v2.17.2
 	github.com/pkg/errors v0.9.1
@@ -0,0 +1,123 @@
+// Package hyphenation finds hyphenation points in words with Liang's algorithm, as used by TeX.
+package hyphenation
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Hyphenator hyphenates words of a single language.
+type Hyphenator struct {
+	patterns   map[string][]int
+	exceptions map[string][]int
+	leftMin    int
+	rightMin   int
+}
+
+// New creates a Hyphenator from whitespace separated TeX patterns (e.g. "hy3ph") and exceptions (e.g. "as-so-ciate").
+// Words aren't hyphenated within their first leftMin and last rightMin letters.
+func New(patterns, exceptions string, leftMin, rightMin int) *Hyphenator {
+	h := &Hyphenator{
+		patterns:   map[string][]int{},
+		exceptions: map[string][]int{},
+		leftMin:    leftMin,
+		rightMin:   rightMin,
+	}
+	for _, p := range strings.Fields(patterns) {
+		letters := []rune{}
+		values := []int{0}
+		for _, r := range p {
+			if r >= '0' && r <= '9' {
+				values[len(values)-1] = int(r - '0')
+				continue
+			}
+			letters = append(letters, r)
+			values = append(values, 0)
+		}
+		h.patterns[string(letters)] = values
+	}
+	for _, e := range strings.Fields(exceptions) {
+		letters := []rune{}
+		points := []int{}
+		for _, r := range e {
+			if r == '-' {
+				points = append(points, len(letters))
+				continue
+			}
+			letters = append(letters, r)
+		}
+		h.exceptions[string(letters)] = points
+	}
+	return h
+}
+
+var (
+	languagesOnce sync.Once
+	languages     map[string]*Hyphenator
+)
+
+// ForLanguage returns the Hyphenator for lang, which is either "en" or "de".
+func ForLanguage(lang string) (*Hyphenator, bool) {
+	languagesOnce.Do(func() {
+		languages = map[string]*Hyphenator{
+			"en": New(patternsEN, exceptionsEN, 2, 3),
+			"de": New(patternsDE, "", 2, 2),
+		}
+	})
+	h, ok := languages[strings.ToLower(lang)]
+	return h, ok
+}
+
+// Hyphenate returns the rune offsets in word, where it may be hyphenated. Leading and trailing
+// non-letters (e.g. punctuation) are ignored, words containing other non-letters aren't hyphenated.
+func (h *Hyphenator) Hyphenate(word string) []int {
+	rs := []rune(word)
+	start, end := 0, len(rs)
+	for start < end && !unicode.IsLetter(rs[start]) {
+		start++
+	}
+	for end > start && !unicode.IsLetter(rs[end-1]) {
+		end--
+	}
+	letters := []rune(strings.ToLower(string(rs[start:end])))
+	if len(letters) != end-start || len(letters) < h.leftMin+h.rightMin {
+		return nil
+	}
+	for _, r := range letters {
+		if !unicode.IsLetter(r) {
+			return nil
+		}
+	}
+
+	points := []int{}
+	if ps, ok := h.exceptions[string(letters)]; ok {
+		for _, p := range ps {
+			points = append(points, start+p)
+		}
+		return points
+	}
+
+	//values[i] rates the point before letter i-1 of the word enclosed in dots
+	s := append(append([]rune{'.'}, letters...), '.')
+	values := make([]int, len(s)+1)
+	for i := range s {
+		for j := i + 1; j <= len(s); j++ {
+			pvs, ok := h.patterns[string(s[i:j])]
+			if !ok {
+				continue
+			}
+			for k, v := range pvs {
+				if v > values[i+k] {
+					values[i+k] = v
+				}
+			}
+		}
+	}
+	for i := h.leftMin; i <= len(letters)-h.rightMin; i++ {
+		if values[i+1]%2 == 1 {
+			points = append(points, start+i)
+		}
+	}
+	return points
+}
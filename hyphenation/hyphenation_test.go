@@ -0,0 +1,39 @@
+package hyphenation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHyphenate(t *testing.T) {
+	//Liang's example patterns for hy-phen-ation
+	liang := New("hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n", "", 2, 3)
+	en, _ := ForLanguage("en")
+	de, _ := ForLanguage("de")
+	tests := []struct {
+		h      *Hyphenator
+		word   string
+		points []int
+	}{
+		{h: liang, word: "hyphenation", points: []int{2, 6}},
+		{h: New("1b", "", 2, 2), word: "aabaab", points: []int{2}},
+		{h: en, word: "computer", points: []int{3}},
+		{h: en, word: "associate", points: []int{2, 4}},
+		{h: en, word: "project", points: []int{}},
+		{h: en, word: "table,", points: []int{2}},
+		{h: en, word: "(table)", points: []int{3}},
+		{h: en, word: "don't", points: nil},
+		{h: en, word: "a", points: nil},
+		{h: de, word: "Silbentrennung", points: []int{3, 6, 10}},
+		{h: de, word: "Donaudampfschiff", points: []int{2, 5, 10}},
+	}
+	for _, test := range tests {
+		points := test.h.Hyphenate(test.word)
+		if len(points) != len(test.points) || (len(points) > 0 && !reflect.DeepEqual(points, test.points)) {
+			t.Errorf("Hyphenate(%q): want %v, got %v", test.word, test.points, points)
+		}
+	}
+	if _, ok := ForLanguage("fr"); ok {
+		t.Errorf("want no hyphenator for fr")
+	}
+}
@@ -0,0 +1,147 @@
+package gompdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/pkg/errors"
+)
+
+// ImageResolver looks up images by their source, e.g. in an object store or a database. It returns a nil reader for
+// sources it doesn't know. Readers implementing io.Closer are closed after reading.
+type ImageResolver interface {
+	ResolveImage(source string) (io.Reader, error)
+}
+
+// WithImage registers the image (PNG, JPEG or GIF) read from r under name, which image instructions use as source.
+func WithImage(name string, r io.Reader) ProcessOption {
+	return func(p *Processor) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return errors.Wrapf(err, "read image (%s)", name)
+		}
+		p.images[name] = b
+		return nil
+	}
+}
+
+// WithImageResolver adds a resolver for image sources, which are neither registered with WithImage nor data URIs.
+// Resolvers are asked in the order they are added, before the source is looked up as file.
+func WithImageResolver(r ImageResolver) ProcessOption {
+	return func(p *Processor) error {
+		p.imageResolvers = append(p.imageResolvers, r)
+		return nil
+	}
+}
+
+func (p *Processor) registerImage(src string) *gofpdf.ImageInfoType {
+	b, err := p.imageData(strings.TrimSpace(src))
+	if err != nil {
+		p.pdf.SetError(err)
+		return nil
+	}
+	imageType, err := sniffImageType(b)
+	if err != nil {
+		p.pdf.SetError(errors.Wrapf(err, "register image (%s)", src))
+		return nil
+	}
+	return p.pdf.RegisterImageOptionsReader(src, gofpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(b))
+}
+
+// imageData returns the data of an image source. Sources are, in this order, looked up in the registered images,
+// decoded as data URI, resolved by the image resolvers or read from file. The data is kept for further layout
+// passes.
+func (p *Processor) imageData(src string) ([]byte, error) {
+	if b, ok := p.images[src]; ok {
+		return b, nil
+	}
+	b, err := p.resolveImage(src)
+	if err != nil {
+		return nil, err
+	}
+	p.images[src] = b
+	return b, nil
+}
+
+func (p *Processor) resolveImage(src string) ([]byte, error) {
+	if strings.HasPrefix(src, "data:") {
+		b, err := decodeDataURI(src)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode image data uri")
+		}
+		return b, nil
+	}
+	for _, resolver := range p.imageResolvers {
+		r, err := resolver.ResolveImage(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolve image (%s)", src)
+		}
+		if r == nil {
+			continue
+		}
+		b, err := ioutil.ReadAll(r)
+		if c, ok := r.(io.Closer); ok {
+			c.Close()
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "read resolved image (%s)", src)
+		}
+		return b, nil
+	}
+	b, err := ioutil.ReadFile(p.imageFile(src))
+	if err != nil {
+		return nil, errors.Wrapf(err, "read image file (%s)", src)
+	}
+	return b, nil
+}
+
+// imageFile returns the path of an image file. Relative files are looked up in the directory of the document's
+// source file first.
+func (p *Processor) imageFile(file string) string {
+	if filepath.IsAbs(file) || p.doc.dir == "" {
+		return file
+	}
+	inDir := filepath.Join(p.doc.dir, file)
+	if _, err := os.Stat(inDir); err == nil {
+		return inDir
+	}
+	return file
+}
+
+// decodeDataURI returns the data of a base64 encoded data URI (data:[<media type>];base64,<data>). White space
+// within the data is ignored.
+func decodeDataURI(uri string) ([]byte, error) {
+	comma := strings.IndexByte(uri, ',')
+	if comma < 0 {
+		return nil, errors.Errorf("missing comma")
+	}
+	if !strings.HasSuffix(uri[:comma], ";base64") {
+		return nil, errors.Errorf("data must be base64 encoded")
+	}
+	data := strings.Join(strings.Fields(uri[comma+1:]), "")
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode base64")
+	}
+	return b, nil
+}
+
+// sniffImageType returns the fpdf image type of the data.
+func sniffImageType(b []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(b, []byte("\x89PNG\r\n\x1a\n")):
+		return "png", nil
+	case bytes.HasPrefix(b, []byte("\xff\xd8\xff")):
+		return "jpg", nil
+	case bytes.HasPrefix(b, []byte("GIF87a")), bytes.HasPrefix(b, []byte("GIF89a")):
+		return "gif", nil
+	default:
+		return "", errors.Errorf("unsupported image type, expected PNG, JPEG or GIF")
+	}
+}
@@ -0,0 +1,83 @@
+package gompdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeDataURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		data string
+		fail bool
+	}{
+		{uri: "data:image/png;base64,aGVsbG8=", data: "hello"},
+		{uri: "data:;base64,aGVs\n  bG8=", data: "hello"},
+		{uri: "data:image/png,hello", fail: true},
+		{uri: "data:image/png;base64", fail: true},
+		{uri: "data:image/png;base64,!!", fail: true},
+	}
+	for _, test := range tests {
+		b, err := decodeDataURI(test.uri)
+		if test.fail {
+			if err == nil {
+				t.Errorf("decodeDataURI(%q): want error", test.uri)
+			}
+			continue
+		}
+		if err != nil || string(b) != test.data {
+			t.Errorf("decodeDataURI(%q): want %q, got %q (%v)", test.uri, test.data, b, err)
+		}
+	}
+}
+
+func TestSniffImageType(t *testing.T) {
+	tests := map[string]string{
+		string(testPNG(t)):   "png",
+		"\xff\xd8\xff\xe0..": "jpg",
+		"GIF89a..":           "gif",
+		"GIF87a..":           "gif",
+		"<svg/>":             "",
+	}
+	for data, want := range tests {
+		imageType, err := sniffImageType([]byte(data))
+		if imageType != want || (want == "") != (err != nil) {
+			t.Errorf("sniffImageType(%q): want (%s), got (%s, %v)", data[:4], want, imageType, err)
+		}
+	}
+}
+
+type testImageResolver map[string][]byte
+
+func (r testImageResolver) ResolveImage(source string) (io.Reader, error) {
+	b, ok := r[source]
+	if !ok {
+		return nil, nil
+	}
+	return bytes.NewReader(b), nil
+}
+
+func TestImageSources(t *testing.T) {
+	png := testPNG(t)
+	src := `<document><body>
+<image>data:image/png;base64,` + base64.StdEncoding.EncodeToString(png) + `</image>
+<image>registered</image>
+<image>resolved</image>
+</body></document>`
+	processTestSource(t, src, WithImage("registered", bytes.NewReader(png)), WithImageResolver(testImageResolver{"resolved": png}))
+
+	doc, err := Load(strings.NewReader(`<document><body><image>missing.png</image></body></document>`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := NewProcessor(doc, WithImageResolver(testImageResolver{}))
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	if err := p.Process(&bytes.Buffer{}); err == nil {
+		t.Errorf("want error for missing image")
+	}
+}
@@ -18,11 +18,24 @@ func init() {
 	instructionRegistry.Register(&Font{})
 	instructionRegistry.Register(&Box{})
 	instructionRegistry.Register(&Text{})
+	instructionRegistry.Register(&Heading{})
+	instructionRegistry.Register(&Toc{})
+	instructionRegistry.Register(&Link{})
+	instructionRegistry.Register(&Anchor{})
+	instructionRegistry.Register(&Section{})
+	instructionRegistry.Register(&PageBreak{})
 	instructionRegistry.Register(&LineFeed{})
 	instructionRegistry.Register(&SetX{})
 	instructionRegistry.Register(&SetY{})
 	instructionRegistry.Register(&SetXY{})
 	instructionRegistry.Register(&Image{})
+	instructionRegistry.Register(&Barcode{})
+	instructionRegistry.Register(&Line{})
+	instructionRegistry.Register(&Rect{})
+	instructionRegistry.Register(&Ellipse{})
+	instructionRegistry.Register(&Polyline{})
+	instructionRegistry.Register(&Path{})
+	instructionRegistry.Register(&Columns{})
 	instructionRegistry.Register(&Table{})
 	instructionRegistry.Register(&TableRow{})
 	instructionRegistry.Register(&TableCell{})
@@ -102,6 +115,19 @@ func (i *Styled) DecodeAttrs(attrs []xml.Attr) error {
 	return nil
 }
 
+func (i *Styled) classNames() []string {
+	return i.Classes
+}
+
+// styleProblems returns the problems of the instruction's style attributes, see style.Applier.Problems.
+func (i *Styled) styleProblems() []error {
+	errs := []error{}
+	for _, app := range i.Appliers {
+		errs = append(errs, app.Problems()...)
+	}
+	return errs
+}
+
 func (i *Styled) Apply(cs style.Classes, styles *style.Styles) {
 	cs.Apply(styles, i.Classes...)
 	for _, app := range i.Appliers {
@@ -156,12 +182,14 @@ type SetXY struct {
 
 type Box struct {
 	Styled
+	Outline
 	XMLName xml.Name `xml:"box"`
 	Text    string   `xml:",chardata"`
 }
 
 type Text struct {
 	Styled
+	Outline
 	XMLName xml.Name `xml:"text"`
 	Text    string   `xml:",chardata"`
 }
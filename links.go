@@ -0,0 +1,138 @@
+package gompdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mazzegi/gompdf/markdown"
+	"github.com/mazzegi/gompdf/style"
+	"github.com/pkg/errors"
+)
+
+// Link writes its text as a link to Href, which is either an external url or #name of an anchor in the document.
+type Link struct {
+	Styled
+	XMLName xml.Name `xml:"a"`
+	Href    string   `xml:"href,attr"`
+	Text    string   `xml:",chardata"`
+}
+
+// Anchor names the current position as target of internal (#name) links.
+type Anchor struct {
+	NoStyles
+	XMLName xml.Name `xml:"anchor"`
+	Name    string   `xml:"name,attr"`
+}
+
+// markdown returns the link as markdown link "[label](target)", to write it inline with the text around it.
+func (l *Link) markdown() string {
+	target := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, " ", `\ `).Replace(strings.TrimSpace(l.Href))
+	return "[" + l.Text + "](" + target + ")"
+}
+
+// UnmarshalXML decodes the text with the links (<a href>) within it, which are written inline.
+func (t *Text) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainText Text
+	err := decodeStartAttrs(start, (*plainText)(t))
+	if err != nil {
+		return err
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := token.(type) {
+		case xml.EndElement:
+			if tok == start.End() {
+				return nil
+			}
+		case xml.StartElement:
+			if tok.Name.Local != "a" {
+				err = d.Skip()
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			l := &Link{}
+			err = d.DecodeElement(l, &tok)
+			if err != nil {
+				return err
+			}
+			t.Text += l.markdown()
+		case xml.CharData:
+			t.Text += string(tok)
+		}
+	}
+}
+
+// decodeStartAttrs decodes the attributes of start into v, like xml.Unmarshal does.
+func decodeStartAttrs(start xml.StartElement, v interface{}) error {
+	buf := &bytes.Buffer{}
+	enc := xml.NewEncoder(buf)
+	enc.EncodeToken(start)
+	enc.EncodeToken(start.End())
+	err := enc.Flush()
+	if err != nil {
+		return errors.Wrapf(err, "encode start element (%s)", start.Name.Local)
+	}
+	return xml.Unmarshal(buf.Bytes(), v)
+}
+
+func (l *Link) items(p *Processor) markdown.Items {
+	items := p.textItems(l.Text)
+	for i := range items {
+		items[i].Link = l.Href
+	}
+	return items
+}
+
+func (p *Processor) renderLink(l *Link, sty style.Styles) {
+	p.writeItems(l.items(p), p.effectiveWidth(sty.Dimension.Width), sty)
+}
+
+// linkID returns the fpdf link of the anchor name, which is created on first use.
+func (p *Processor) linkID(name string) int {
+	if id, ok := p.links[name]; ok {
+		return id
+	}
+	id := p.pdf.AddLink()
+	p.links[name] = id
+	return id
+}
+
+// setAnchor points the anchor name to position y on the current page.
+func (p *Processor) setAnchor(name string, y float64) {
+	p.pdf.SetLink(p.linkID(name), y, -1)
+	p.anchors[name] = true
+}
+
+// resolveLinks points links to undefined anchors to the first page, as fpdf fails on unset links.
+func (p *Processor) resolveLinks() {
+	for name, id := range p.links {
+		if !p.anchors[name] {
+			p.problem(errors.Errorf("link to undefined anchor (%s)", name))
+			p.pdf.SetLink(id, 0, 1)
+		}
+	}
+}
+
+func (p *Processor) writeLink(height float64, mdWord markdown.Item, sty style.Styles) {
+	cr := sty.Color.Link
+	p.pdf.SetTextColor(int(cr.R), int(cr.G), int(cr.B))
+	if strings.HasPrefix(mdWord.Link, "#") {
+		p.pdf.WriteLinkID(height, mdWord.Text, p.linkID(strings.TrimPrefix(mdWord.Link, "#")))
+	} else {
+		p.pdf.WriteLinkString(height, mdWord.Text, mdWord.Link)
+	}
+	cr = sty.Color.Text
+	p.pdf.SetTextColor(int(cr.R), int(cr.G), int(cr.B))
+}
+
+// tocAnchor returns the name of the anchor of the i-th table of contents entry.
+func tocAnchor(i int) string {
+	return fmt.Sprintf("toc:%d", i)
+}
@@ -0,0 +1,47 @@
+package gompdf
+
+import "testing"
+
+func TestInlineLinks(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<body>
+<text bookmark="intro">See <a href="https://en.wikipedia.org/wiki/Go_(language)">the docs</a>, or <a href="#a b">here</a>.</text>
+<table><tr><td>Cell <a href="#c">link</a> text</td></tr></table>
+</body>
+</document>`)
+	text := p.doc.Body.iss[0].(*Text)
+	if text.Bookmark != "intro" {
+		t.Errorf("want bookmark (intro), got (%s)", text.Bookmark)
+	}
+	links := map[string]string{}
+	for _, item := range p.textItems(text.Text) {
+		links[item.Text] = item.Link
+	}
+	want := map[string]string{
+		"See ": "",
+		"the ": "https://en.wikipedia.org/wiki/Go_(language)",
+		"docs": "https://en.wikipedia.org/wiki/Go_(language)",
+		"here": "#a b",
+	}
+	for word, link := range want {
+		if got, ok := links[word]; !ok || got != link {
+			t.Errorf("word (%s): want link (%s), got (%s)", word, link, got)
+		}
+	}
+
+	cell := p.doc.Body.iss[1].(*Table).Rows[0].Cells[0]
+	flow := cell.flow()
+	if len(flow) != 1 {
+		t.Fatalf("want the cell's text and link in one text, got %d instructions", len(flow))
+	}
+	found := false
+	for _, item := range p.textItems(flow[0].(*Text).Text) {
+		found = found || (item.Text == "link" && item.Link == "#c")
+	}
+	if !found {
+		t.Errorf("want inline link in cell, got %q", flow[0].(*Text).Text)
+	}
+	if diags := Validate(p.doc); len(diags) > 0 {
+		t.Errorf("want no diagnostics, got %v", diags)
+	}
+}
@@ -0,0 +1,70 @@
+package gompdf
+
+import (
+	"math"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+// marginEnd is where the bottom margin of the last block ended. A block starting there collapses its top margin
+// with that bottom margin.
+type marginEnd struct {
+	page   int
+	y      float64
+	bottom float64
+}
+
+// blockMargin returns the margin of instructions laid out as blocks. Floating images are no blocks.
+func (p *Processor) blockMargin(i Instruction) (style.Margin, bool) {
+	switch i.(type) {
+	case *Box, *Text, *Heading, *Image, *Table:
+		sty := p.appliedStyles(i)
+		if p.floating(i, sty) {
+			return style.Margin{}, false
+		}
+		return sty.Box.Margin, true
+	default:
+		return style.Margin{}, false
+	}
+}
+
+// collapsedTop returns the top margin of a block following a block with bottom margin prevBottom, which
+// only adds the space exceeding the previous bottom margin.
+func collapsedTop(top, prevBottom float64) float64 {
+	return math.Max(0, top-math.Max(0, prevBottom))
+}
+
+// withMargin runs fn for a block with margin m. The top margin is collapsed with the bottom margin of a directly
+// preceding block, the frame is narrowed by the left and right margins and the bottom margin follows the block.
+func (p *Processor) withMargin(m style.Margin, fn func()) {
+	if m == (style.Margin{}) {
+		fn()
+		p.lastMargin = marginEnd{}
+		return
+	}
+	top := m.Top
+	if last := p.lastMargin; last.page == p.pdf.PageNo() && last.y == p.pdf.GetY() {
+		top = collapsedTop(m.Top, last.bottom)
+	}
+	if top != 0 {
+		p.pdf.SetY(p.pdf.GetY() + top)
+	}
+	p.withHorizontalMargin(m, func() {
+		l, _, _, _ := p.pdf.GetMargins()
+		p.pdf.SetX(l)
+		fn()
+	})
+	p.pdf.SetY(p.pdf.GetY() + m.Bottom)
+	p.lastMargin = marginEnd{page: p.pdf.PageNo(), y: p.pdf.GetY(), bottom: m.Bottom}
+}
+
+// withHorizontalMargin runs fn in the current frame narrowed by the left and right margins.
+func (p *Processor) withHorizontalMargin(m style.Margin, fn func()) {
+	if m.Left == 0 && m.Right == 0 {
+		fn()
+		return
+	}
+	l, _, r, _ := p.pdf.GetMargins()
+	pw, _ := p.pdf.GetPageSize()
+	p.withFrame(l+m.Left, pw-l-r-m.Left-m.Right, fn)
+}
@@ -0,0 +1,107 @@
+package gompdf
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+func TestCollapsedTop(t *testing.T) {
+	tests := []struct {
+		top, prevBottom, want float64
+	}{
+		{top: 0, prevBottom: 0, want: 0},
+		{top: 5, prevBottom: 0, want: 5},
+		{top: 5, prevBottom: 3, want: 2},
+		{top: 3, prevBottom: 5, want: 0},
+		{top: 5, prevBottom: 5, want: 0},
+		{top: 5, prevBottom: -2, want: 5},
+		{top: -2, prevBottom: 0, want: 0},
+	}
+	for _, test := range tests {
+		if got := collapsedTop(test.top, test.prevBottom); got != test.want {
+			t.Errorf("collapsedTop(%v, %v): want %v, got %v", test.top, test.prevBottom, test.want, got)
+		}
+	}
+}
+
+var textStartRx = regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td \((first|second)\)Tj`)
+
+func TestBlockMargins(t *testing.T) {
+	k := 72 / 25.4
+	tests := []struct {
+		name   string
+		body   string
+		gap    float64
+		indent float64
+	}{
+		{name: "no margins", body: `<text>first</text><text>second</text>`},
+		{name: "bottom margin", body: `<text style="margin: 0,0,0,5">first</text><text>second</text>`, gap: 5},
+		{name: "top margin", body: `<text>first</text><text style="margin: 0,5,0,0">second</text>`, gap: 5},
+		{name: "collapsed smaller top", body: `<text style="margin: 0,0,0,5">first</text><text style="margin: 0,3,0,0">second</text>`, gap: 5},
+		{name: "collapsed larger top", body: `<text style="margin: 0,0,0,3">first</text><text style="margin: 0,5,0,0">second</text>`, gap: 5},
+		{name: "left margin", body: `<text>first</text><text style="margin: 10,0,10,0">second</text>`, indent: 10},
+		{name: "non-block between", body: `<text style="margin: 0,0,0,3">first</text><font/><text style="margin: 0,5,0,0">second</text>`, gap: 5},
+	}
+	p := loadTestProcessor(t, `<document><body/></document>`)
+	lh := p.lineHeight(DefaultStyle)
+	for _, test := range tests {
+		content := pdfContent(t, processTestSource(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>`+test.body+`</body>
+</document>`))
+		ms := textStartRx.FindAllStringSubmatch(content, -1)
+		if len(ms) != 2 {
+			t.Errorf("%s: want both texts written, got %v", test.name, ms)
+			continue
+		}
+		x1, _ := strconv.ParseFloat(ms[0][1], 64)
+		y1, _ := strconv.ParseFloat(ms[0][2], 64)
+		x2, _ := strconv.ParseFloat(ms[1][1], 64)
+		y2, _ := strconv.ParseFloat(ms[1][2], 64)
+		if gap := (y1-y2)/k - lh; math.Abs(gap-test.gap) > 0.05 {
+			t.Errorf("%s: want gap %v, got %v", test.name, test.gap, gap)
+		}
+		if indent := (x2 - x1) / k; math.Abs(indent-test.indent) > 0.05 {
+			t.Errorf("%s: want indent %v, got %v", test.name, test.indent, indent)
+		}
+	}
+}
+
+func TestBlockMargin(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<text style="margin: 1,2,3,4">text</text>
+<box style="margin: 1,2,3,4">box</box>
+<table style="margin: 1,2,3,4"><tr><td>cell</td></tr></table>
+<image style="width: 10; height: 10; margin: 1,2,3,4">img</image>
+<image style="float: left; width: 10; height: 10; margin: 1,2,3,4">img</image>
+<lf/>
+</body>
+</document>`, WithImage("img", bytes.NewReader(testPNG(t))))
+	m := style.Margin{Left: 1, Top: 2, Right: 3, Bottom: 4}
+	tests := []struct {
+		block  bool
+		margin style.Margin
+	}{
+		{block: true, margin: m},
+		{block: true, margin: m},
+		{block: true, margin: m},
+		{block: true, margin: m},
+		//floating images are no blocks, their margin separates them from the text
+		{block: false},
+		{block: false},
+	}
+	for k, test := range tests {
+		i := p.doc.Body.iss[k]
+		margin, block := p.blockMargin(i)
+		if block != test.block || margin != test.margin {
+			t.Errorf("(%T): want block %t with margin %v, got %t with %v", i, test.block, test.margin, block, margin)
+		}
+	}
+}
@@ -11,6 +11,9 @@ const underscore = "_"
 const underscores = "__"
 const backtick = "`"
 const backslash = `\`
+const linkStart = "["
+const linkMid = "]("
+const linkEnd = ")"
 
 type Item struct {
 	Text    string
@@ -18,21 +21,24 @@ type Item struct {
 	Bold    bool
 	Code    bool
 	Newline bool
+	//Link is the target of a [label](target) link, the label being the item's text
+	Link string
 }
 
 func (i Item) String() string {
-	return fmt.Sprintf("%q: italic=%t, bold=%t, code=%t, nl=%t", i.Text, i.Italic, i.Bold, i.Code, i.Newline)
+	return fmt.Sprintf("%q: italic=%t, bold=%t, code=%t, nl=%t, link=%q", i.Text, i.Italic, i.Bold, i.Code, i.Newline, i.Link)
 }
 
 type Items []Item
 
-func (is *Items) add(text string, italic, bold, code bool, nl bool) {
+func (is *Items) add(text string, italic, bold, code bool, nl bool, link string) {
 	*is = append(*is, Item{
 		Text:    text,
 		Italic:  italic,
 		Bold:    bold,
 		Code:    code,
 		Newline: nl,
+		Link:    link,
 	})
 }
 
@@ -53,7 +59,7 @@ func (i Item) WordItems(tr func(string) string) Items {
 		words = append(words, currWord)
 	}
 	for _, word := range words {
-		is.add(tr(word), i.Italic, i.Bold, i.Code, i.Newline)
+		is.add(tr(word), i.Italic, i.Bold, i.Code, i.Newline, i.Link)
 	}
 	return is
 }
@@ -82,31 +88,46 @@ func (p *Processor) Process(s string) Items {
 	bold := false
 	italic := false
 	code := false
+	link := ""
+	//labelEnd is the index of the "](" closing the label of the current link, resume the index after the link
+	labelEnd := -1
+	resume := -1
 	i := 0
 	for {
 		if i >= len(s) {
 			return items
 		}
 		b := bs[i]
-		if strings.HasPrefix(s[i:], asterisks) || strings.HasPrefix(s[i:], underscores) {
+		if i == labelEnd {
+			link = ""
+			items.add("", italic, bold, code, false, link)
+			i = resume
+			labelEnd, resume = -1, -1
+		} else if target, n := linkTarget(s[i:]); !code && link == "" && target != "" {
+			link = target
+			labelEnd = i + strings.Index(s[i:], linkMid)
+			resume = i + n
+			items.add("", italic, bold, code, false, link)
+			i += 1
+		} else if strings.HasPrefix(s[i:], asterisks) || strings.HasPrefix(s[i:], underscores) {
 			bold = !bold
-			items.add("", italic, bold, code, false)
+			items.add("", italic, bold, code, false, link)
 			i += 2
 		} else if strings.HasPrefix(s[i:], asterisk) || strings.HasPrefix(s[i:], underscore) {
 			italic = !italic
-			items.add("", italic, bold, code, false)
+			items.add("", italic, bold, code, false, link)
 			i += 1
 		} else if strings.HasPrefix(s[i:], backtick) {
 			code = !code
-			items.add("", italic, bold, code, false)
+			items.add("", italic, bold, code, false, link)
 			i += 1
 		} else if strings.HasPrefix(s[i:], backslash) {
-			items.add(string(b), italic, bold, code, true)
-			items.add("", italic, bold, code, false)
+			items.add(string(b), italic, bold, code, true, link)
+			items.add("", italic, bold, code, false, link)
 			i += 1
 		} else {
 			if len(items) == 0 {
-				items.add("", italic, bold, code, false)
+				items.add("", italic, bold, code, false, link)
 			}
 			ibs := []byte(items[len(items)-1].Text)
 			ibs = append(ibs, b)
@@ -115,3 +136,40 @@ func (p *Processor) Process(s string) Items {
 		}
 	}
 }
+
+// linkTarget returns the target of a link "[label](target)" at the start of s and the length of the link, or "" if
+// s doesn't start with a link. Parentheses in the target are either balanced or escaped by a backslash, like spaces.
+func linkTarget(s string) (string, int) {
+	if !strings.HasPrefix(s, linkStart) {
+		return "", 0
+	}
+	mid := strings.Index(s, linkMid)
+	if mid < 0 || strings.LastIndex(s[:mid], linkStart) > 0 {
+		return "", 0
+	}
+	target := []byte{}
+	depth := 0
+	for i := mid + len(linkMid); i < len(s); i++ {
+		switch b := s[i]; {
+		case b == '\\' && i+1 < len(s):
+			i++
+			target = append(target, s[i])
+		case b == ' ' || b == '\n':
+			return "", 0
+		case b == '(':
+			depth++
+			target = append(target, b)
+		case b == ')' && depth > 0:
+			depth--
+			target = append(target, b)
+		case b == ')':
+			if len(target) == 0 {
+				return "", 0
+			}
+			return string(target), i + len(linkEnd)
+		default:
+			target = append(target, b)
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,48 @@
+package markdown
+
+import "testing"
+
+func TestLinkTarget(t *testing.T) {
+	tests := []struct {
+		s      string
+		target string
+		n      int
+	}{
+		{s: "[label](https://example.org) rest", target: "https://example.org", n: 28},
+		{s: "[label](https://en.wikipedia.org/wiki/Go_(language)) rest", target: "https://en.wikipedia.org/wiki/Go_(language)", n: 52},
+		{s: `[label](a\)b\ c) rest`, target: "a)b c", n: 16},
+		{s: "[label](a(b) rest", target: "", n: 0},
+		{s: "[label](a b)", target: "", n: 0},
+		{s: "[label]()", target: "", n: 0},
+		{s: "label](a)", target: "", n: 0},
+	}
+	for _, test := range tests {
+		target, n := linkTarget(test.s)
+		if target != test.target || n != test.n {
+			t.Errorf("linkTarget(%q): want (%q, %d), got (%q, %d)", test.s, test.target, test.n, target, n)
+		}
+	}
+}
+
+func TestProcessLinkWithParentheses(t *testing.T) {
+	items := NewProcessor().Process("see [Go](https://en.wikipedia.org/wiki/Go_(language)) now")
+	want := []Item{
+		{Text: "see "},
+		{Text: "Go", Link: "https://en.wikipedia.org/wiki/Go_(language)"},
+		{Text: " now"},
+	}
+	texts := Items{}
+	for _, item := range items {
+		if item.Text != "" {
+			texts = append(texts, item)
+		}
+	}
+	if len(texts) != len(want) {
+		t.Fatalf("want %v, got %v", want, texts)
+	}
+	for i, item := range texts {
+		if item != want[i] {
+			t.Errorf("item %d: want %v, got %v", i, want[i], item)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package gompdf
+
+import (
+	"math"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/mazzegi/gompdf/style"
+)
+
+// instructionsHeight returns the vertical space the instructions take when processed in the current frame.
+// It mirrors processInstructions without rendering anything. Absolute positioning instructions are ignored.
+func (p *Processor) instructionsHeight(is []Instruction) float64 {
+	saved := p.currStyles
+	defer func() {
+		p.currStyles = saved
+		p.applyFont(saved.Font)
+	}()
+
+	height := float64(0)
+	//prevBottom is the bottom margin of the preceding block, or -1 if the preceding instruction is no block
+	prevBottom := float64(-1)
+	//floatBottom is where the floats end, which the flow passes or which a clearing block starts below
+	floatBottom := float64(0)
+	for _, i := range is {
+		if p.clearsFloats(i) {
+			height = math.Max(height, floatBottom)
+		}
+		m, block := p.blockMargin(i)
+		if !block {
+			floatBottom = math.Max(floatBottom, height+p.floatHeight(i))
+			h := p.instructionHeight(i)
+			if h != 0 {
+				prevBottom = -1
+			}
+			height += h
+			continue
+		}
+		height += collapsedTop(m.Top, prevBottom) + m.Bottom
+		p.withHorizontalMargin(m, func() {
+			height += p.instructionHeight(i)
+		})
+		prevBottom = m.Bottom
+	}
+	return math.Max(height, floatBottom)
+}
+
+func (p *Processor) instructionHeight(i Instruction) float64 {
+	switch i := i.(type) {
+	case *Font:
+		i.Apply(p.doc.styleClasses, &p.currStyles)
+		p.applyFont(p.currStyles.Font)
+	case *LineFeed:
+		_, fontHeight := p.pdf.GetFontSize()
+		return fontHeight * i.Lines
+	case *Box:
+		sty := p.appliedStyles(i)
+		return sty.Dimension.OffsetY + p.textBoxHeight(i.Text, sty) + sty.Box.Padding.Top + sty.Box.Padding.Bottom + sty.Dimension.LineHeight
+	case *Text:
+		sty := p.appliedStyles(i)
+		return p.textHeight(i.Text, p.effectiveWidth(sty.Dimension.Width), sty)
+	case *Heading:
+		sty := p.appliedStyles(i)
+		return p.textHeight(i.Text, p.effectiveWidth(sty.Dimension.Width), sty)
+	case *Link:
+		sty := p.appliedStyles(i)
+		return p.itemsHeight(i.items(p), p.effectiveWidth(sty.Dimension.Width), sty)
+	case *Table:
+		sty := p.appliedStyles(i)
+		return p.tableHeight(i, sty) + p.lineHeight(sty)
+	case *Toc:
+		return p.tocHeight(p.appliedStyles(i))
+	case *Image:
+		sty := p.appliedStyles(i)
+		info := p.registerImage(i.Source)
+		if info == nil || p.floating(i, sty) {
+			return 0
+		}
+		_, h := p.imageSize(info, sty)
+		return sty.Dimension.OffsetY + h
+	case *Barcode:
+		sty := p.appliedStyles(i)
+		code := p.barcode(i)
+		if code == nil {
+			return 0
+		}
+		return sty.Dimension.OffsetY + p.barcodeHeight(i, code, sty)
+	case shape:
+		return p.shapeHeight(i)
+	case *Columns:
+		return p.columnsHeight(i, p.appliedStyles(i))
+	default:
+		return p.customHeight(i)
+	}
+	return 0
+}
+
+// instructionsWidths returns the minimal and maximal width the instructions need. The minimal width is
+// the widest unbreakable content, the maximal width the widest content without any wrapping.
+func (p *Processor) instructionsWidths(is []Instruction) (float64, float64) {
+	saved := p.currStyles
+	defer func() {
+		p.currStyles = saved
+		p.applyFont(saved.Font)
+	}()
+
+	min, max := float64(0), float64(0)
+	for _, i := range is {
+		imin, imax := p.instructionWidths(i)
+		if m, block := p.blockMargin(i); block {
+			imin += m.Left + m.Right
+			imax += m.Left + m.Right
+		}
+		if imin > min {
+			min = imin
+		}
+		if imax > max {
+			max = imax
+		}
+	}
+	return min, max
+}
+
+func (p *Processor) instructionWidths(i Instruction) (float64, float64) {
+	switch i := i.(type) {
+	case *Font:
+		i.Apply(p.doc.styleClasses, &p.currStyles)
+		p.applyFont(p.currStyles.Font)
+	case *Box:
+		sty := p.appliedStyles(i)
+		if sty.Dimension.Width > 0 {
+			return sty.Dimension.OffsetX + sty.Dimension.Width, sty.Dimension.OffsetX + sty.Dimension.Width
+		}
+		tmin, tmax := p.textWidths(i.Text, sty.Font)
+		extra := sty.Dimension.OffsetX + sty.Box.Padding.Left + sty.Box.Padding.Right + 3
+		return tmin + extra, tmax + extra
+	case *Text:
+		sty := p.appliedStyles(i)
+		if sty.Dimension.Width > 0 {
+			return sty.Dimension.Width, sty.Dimension.Width
+		}
+		return p.textWidths(i.Text, sty.Font)
+	case *Heading:
+		sty := p.appliedStyles(i)
+		return p.textWidths(i.Text, sty.Font)
+	case *Link:
+		sty := p.appliedStyles(i)
+		return p.itemsWidths(i.items(p), sty.Font)
+	case *Image:
+		sty := p.appliedStyles(i)
+		info := p.registerImage(i.Source)
+		if info == nil {
+			return 0, 0
+		}
+		w, _ := p.imageSize(info, sty)
+		return sty.Dimension.OffsetX + w, sty.Dimension.OffsetX + w
+	case *Barcode:
+		sty := p.appliedStyles(i)
+		code := p.barcode(i)
+		if code == nil {
+			return 0, 0
+		}
+		w, _ := p.barcodeSize(code, sty)
+		return sty.Dimension.OffsetX + w, sty.Dimension.OffsetX + w
+	case shape:
+		w := p.shapeWidth(i)
+		return w, w
+	case *Columns:
+		return p.columnsWidths(i, p.appliedStyles(i))
+	default:
+		return p.customWidths(i)
+	}
+	return 0, 0
+}
+
+// textBoxHeight returns the inner height of a box, without paddings.
+func (p *Processor) textBoxHeight(text string, sty style.Styles) float64 {
+	if sty.Dimension.Height >= 0 {
+		return sty.Dimension.Height
+	}
+	textWidth := p.effectiveWidth(sty.Dimension.Width) - sty.Box.Padding.Left - sty.Box.Padding.Right - 3 //without -2 it writes over the border
+	if text == "" {
+		text = "Üg"
+	}
+	return p.textHeight(text, textWidth, sty)
+}
+
+// lineHeight returns the height of a single text line written with sty.
+func (p *Processor) lineHeight(sty style.Styles) float64 {
+	return p.pdf.PointConvert(sty.Font.PointSize) * sty.Dimension.LineHeight
+}
+
+// imageSize returns the rendered size of an image. Missing dimensions are derived from the image's aspect ratio.
+func (p *Processor) imageSize(info *gofpdf.ImageInfoType, sty style.Styles) (float64, float64) {
+	w, h := sty.Dimension.Width, sty.Dimension.Height
+	switch {
+	case w <= 0 && h <= 0:
+		w, h = info.Extent()
+	case w <= 0:
+		w = h * info.Width() / info.Height()
+	case h <= 0:
+		h = w * info.Height() / info.Width()
+	}
+	return w, h
+}
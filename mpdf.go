@@ -5,7 +5,11 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/mazzegi/gompdf/style"
@@ -14,9 +18,21 @@ import (
 )
 
 func ParseAndBuild(source string, target string) error {
+	return build(source, target, func() (*Document, error) {
+		return LoadFromFile(source)
+	})
+}
+
+func ParseAndBuildTemplate(source string, target string, data interface{}, funcs template.FuncMap) error {
+	return build(source, target, func() (*Document, error) {
+		return LoadTemplateFromFile(source, data, funcs)
+	})
+}
+
+func build(source string, target string, load func() (*Document, error)) error {
 	start := time.Now()
 	fmt.Printf("load (%s) ...\n", source)
-	doc, err := LoadFromFile(source)
+	doc, err := load()
 	if err != nil {
 		return err
 	}
@@ -43,25 +59,120 @@ func ParseAndBuild(source string, target string) error {
 }
 
 func Load(r io.Reader) (*Document, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read-all")
+	}
+	return loadBytes(b)
+}
+
+func loadBytes(b []byte) (*Document, error) {
+	doc, line, err := decodeDocument(b)
+	if err != nil {
+		if _, ok := err.(*xml.SyntaxError); ok || line == 0 {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "line %d", line)
+	}
+	return doc, nil
+}
+
+// decodeDocument returns the input line an XML decode error occurred on, or 0 if the error is not
+// related to a position.
+func decodeDocument(b []byte) (*Document, int, error) {
 	doc := &Document{}
-	err := xml.NewDecoder(r).Decode(doc)
+	d := xml.NewDecoder(bytes.NewReader(b))
+	err := d.Decode(doc)
 	if err != nil {
-		return nil, err
+		if serr, ok := err.(*xml.SyntaxError); ok {
+			return nil, serr.Line, err
+		}
+		return nil, lineAt(b, d.InputOffset()), err
 	}
 	doc.styleClasses, err = style.DecodeClasses(bytes.NewBufferString(doc.Style))
+	if err != nil {
+		return nil, 0, err
+	}
+	doc.source = b
+	return doc, 0, nil
+}
+
+func LoadTemplate(r io.Reader, data interface{}, funcs template.FuncMap) (*Document, error) {
+	return loadTemplate("document", r, data, funcs)
+}
+
+func LoadTemplateFromFile(file string, data interface{}, funcs template.FuncMap) (*Document, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Errorf("open (%s)", file)
+	}
+	defer f.Close()
+	doc, err := loadTemplate(filepath.Base(file), f, data, funcs)
 	if err != nil {
 		return nil, err
 	}
+	doc.dir = filepath.Dir(file)
+	return doc, nil
+}
+
+// loadTemplate executes the template before decoding the resulting XML. Template errors carry
+// their own "name:line:col" positions. XML errors refer to the executed output, so they are mapped
+// back to the template line and the offending output line is quoted.
+func loadTemplate(name string, r io.Reader, data interface{}, funcs template.FuncMap) (*Document, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read-all")
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(string(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse template")
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "execute template")
+	}
+	out := buf.Bytes()
+	doc, line, err := decodeDocument(out)
+	if err != nil {
+		if line == 0 {
+			return nil, errors.Wrapf(err, "decode executed template (%s)", name)
+		}
+		if tl := templateLine(name, string(b), data, funcs, out, line); tl > 0 {
+			return nil, errors.Wrapf(err, "decode executed template (%s) at template line %d (output line %d: %q)", name, tl, line, sourceLine(out, line))
+		}
+		return nil, errors.Wrapf(err, "decode executed template (%s) at output line %d: %q", name, line, sourceLine(out, line))
+	}
 	return doc, nil
 }
 
+func lineAt(b []byte, offset int64) int {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
+
+func sourceLine(b []byte, line int) string {
+	lines := strings.Split(string(b), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
 func LoadFromFile(file string) (*Document, error) {
 	f, err := os.Open(file)
 	if err != nil {
 		return nil, errors.Errorf("open (%s)", file)
 	}
 	defer f.Close()
-	return Load(f)
+	doc, err := Load(f)
+	if err != nil {
+		return nil, err
+	}
+	doc.dir = filepath.Dir(file)
+	return doc, nil
 }
 
 func (doc *Document) StyleClasses() style.Classes {
@@ -110,14 +221,18 @@ type PageMargins struct {
 }
 
 type Document struct {
-	XMLName      xml.Name `xml:"document"`
-	Meta         Meta     `xml:"meta"`
-	Default      Default  `xml:"default"`
-	Style        string   `xml:"style"`
+	XMLName      xml.Name     `xml:"document"`
+	Meta         Meta         `xml:"meta"`
+	Default      Default      `xml:"default"`
+	Fonts        []FontSource `xml:"fonts>font"`
+	Style        string       `xml:"style"`
 	styleClasses style.Classes
-	Header       Instructions `xml:"header"`
-	Footer       Instructions `xml:"footer"`
-	Body         Instructions `xml:"body"`
+	source       []byte
+	//dir is the directory of the source file, where relative image files are looked up first
+	dir    string
+	Header Instructions `xml:"header"`
+	Footer Instructions `xml:"footer"`
+	Body   Instructions `xml:"body"`
 }
 
 type Meta struct {
@@ -127,6 +242,16 @@ type Meta struct {
 	Subject string   `xml:"subject"`
 }
 
+// FontSource declares a TrueType font file, which is embedded with UTF-8 support. The font is available
+// as font-family in styles. Style and weight select the variant the file provides.
+type FontSource struct {
+	XMLName xml.Name         `xml:"font"`
+	Family  string           `xml:"family,attr"`
+	Style   style.FontStyle  `xml:"style,attr"`
+	Weight  style.FontWeight `xml:"weight,attr"`
+	File    string           `xml:"file,attr"`
+}
+
 type Default struct {
 	XMLName     xml.Name      `xml:"default"`
 	Orientation Orientation   `xml:"orientation"`
@@ -0,0 +1,121 @@
+package gompdf
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mazzegi/gompdf/markdown"
+	"github.com/mazzegi/gompdf/style"
+)
+
+// Outline marks an instruction as entry of the document outline (bookmarks) and/or of the table of contents.
+// Levels start at 1, a toc-level of 0 excludes the instruction from the table of contents.
+// Anchor names the instruction's position as target of internal (#name) links.
+type Outline struct {
+	Bookmark      string `xml:"bookmark,attr"`
+	BookmarkLevel int    `xml:"bookmark-level,attr"`
+	TocLevel      int    `xml:"toc-level,attr"`
+	Anchor        string `xml:"anchor,attr"`
+}
+
+func (ol Outline) isSet() bool {
+	return ol.Bookmark != "" || ol.TocLevel > 0 || ol.Anchor != ""
+}
+
+// title returns the bookmark, if set, or the plain text of the marked instruction.
+func (ol Outline) title(text string) string {
+	if ol.Bookmark != "" {
+		return ol.Bookmark
+	}
+	return plainText(text)
+}
+
+// Heading is a text, which is added to the outline with its level. It's styled by the class h<level>
+// (e.g. h1), if defined, before its own classes.
+type Heading struct {
+	Styled
+	XMLName xml.Name `xml:"h"`
+	Level   int      `xml:"level,attr"`
+	Anchor  string   `xml:"anchor,attr"`
+	Text    string   `xml:",chardata"`
+}
+
+func (h *Heading) Apply(cs style.Classes, styles *style.Styles) {
+	cs.Apply(styles, h.levelClass())
+	h.Styled.Apply(cs, styles)
+}
+
+func (h *Heading) ApplyWithSelector(sel string, cs style.Classes, styles *style.Styles) {
+	cs.ApplyWithSelector(sel, styles, h.levelClass())
+	h.Styled.ApplyWithSelector(sel, cs, styles)
+}
+
+func (h *Heading) levelClass() string {
+	return fmt.Sprintf("h%d", h.level())
+}
+
+func (h *Heading) level() int {
+	if h.Level < 1 {
+		return 1
+	}
+	return h.Level
+}
+
+func (h *Heading) outline() Outline {
+	return Outline{
+		Bookmark:      plainText(h.Text),
+		BookmarkLevel: h.level(),
+		TocLevel:      h.level(),
+		Anchor:        h.Anchor,
+	}
+}
+
+// plainText returns the normalized text without markdown.
+func plainText(s string) string {
+	ts := []string{}
+	for _, item := range markdown.NewProcessor().Process(s) {
+		if !item.Newline {
+			ts = append(ts, item.Text)
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(ts, " ")), " ")
+}
+
+func (p *Processor) renderHeading(h *Heading, sty style.Styles) {
+	p.renderText(&Text{Text: h.Text, Outline: h.outline()}, sty)
+}
+
+// markOutline adds the bookmark, table of contents entry and anchor ol declares for position y on the current page.
+func (p *Processor) markOutline(ol Outline, text string, fnt style.Font, y float64) {
+	p.addBookmark(ol, fnt, y)
+	if ol.Anchor != "" {
+		p.setAnchor(ol.Anchor, y)
+	}
+	if ol.TocLevel > 0 {
+		p.setAnchor(tocAnchor(len(p.tocCollected)), y)
+		p.tocCollected = append(p.tocCollected, tocEntry{
+			title: ol.title(text),
+			level: ol.TocLevel,
+			page:  p.pdf.PageNo(),
+		})
+	}
+}
+
+// addBookmark adds an outline entry for position y on the current page, if ol declares one.
+// Nesting is limited to one level below the previous entry, as deeper jumps break the outline.
+func (p *Processor) addBookmark(ol Outline, fnt style.Font, y float64) {
+	if ol.Bookmark == "" {
+		return
+	}
+	level := ol.BookmarkLevel - 1
+	if level < 0 {
+		level = 0
+	}
+	if level > p.bookmarkLevel+1 {
+		level = p.bookmarkLevel + 1
+	}
+	p.bookmarkLevel = level
+	p.applyFont(fnt)
+	p.pdf.Bookmark(p.textTransformer(fnt)(ol.Bookmark), level, y)
+}
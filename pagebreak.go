@@ -0,0 +1,90 @@
+package gompdf
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/pkg/errors"
+)
+
+// PageBreak starts a new page. Orientation, format and margins (left,top,right,bottom), if set, apply to the new
+// and all following pages. Unset, they are taken from the current page. Margins of page breaks within
+// columns are ignored.
+type PageBreak struct {
+	NoStyles
+	XMLName     xml.Name    `xml:"page-break"`
+	Orientation Orientation `xml:"orientation,attr"`
+	Format      Format      `xml:"format,attr"`
+	Margins     string      `xml:"margins,attr"`
+	margins     *PageMargins
+}
+
+func (pb *PageBreak) DecodeAttrs(attrs []xml.Attr) error {
+	switch pb.Orientation {
+	case "", OrientationPortrait, OrientationLandscape:
+	default:
+		return errors.Errorf("invalid page orientation (%s)", pb.Orientation)
+	}
+	switch pb.Format {
+	case "", FormatA3, FormatA4, FormatA5, FormatLetter, FormatLegal:
+	default:
+		return errors.Errorf("invalid page format (%s)", pb.Format)
+	}
+	if pb.Margins != "" {
+		m := &PageMargins{}
+		_, err := fmt.Sscanf(pb.Margins, "%f,%f,%f,%f", &m.Left, &m.Top, &m.Right, &m.Bottom)
+		if err != nil {
+			return errors.Wrapf(err, "scan page margins (%s)", pb.Margins)
+		}
+		pb.margins = m
+	}
+	return nil
+}
+
+func (p *Processor) processPageBreak(pb *PageBreak) {
+	if pb.margins != nil && p.frames > 0 {
+		//frames restore their margins, when they end
+		p.problem(errors.Errorf("page margins (%s) of a page break within columns are ignored", pb.Margins))
+	} else if pb.margins != nil {
+		auto, _ := p.pdf.GetAutoPageBreak()
+		p.pdf.SetMargins(pb.margins.Left, pb.margins.Top, pb.margins.Right)
+		p.pdf.SetAutoPageBreak(auto, pb.margins.Bottom)
+		p.pageMargins = *pb.margins
+	}
+	if pb.Orientation == "" && pb.Format == "" {
+		p.newPage()
+		return
+	}
+	p.firstColumn()
+	w, h := p.pdf.GetPageSize()
+	size := gofpdf.SizeType{Wd: math.Min(w, h), Ht: math.Max(w, h)}
+	if pb.Format != "" {
+		size = p.pdf.GetPageSizeStr(fpdfFormat(pb.Format))
+	}
+	orientation := "P"
+	if w > h {
+		orientation = "L"
+	}
+	if pb.Orientation != "" {
+		orientation = fpdfOrientation(pb.Orientation)
+	}
+	p.pdf.AddPageFormat(orientation, size)
+}
+
+// addPage continues the flow on a new page, or in the next column within columns.
+func (p *Processor) addPage() {
+	if p.nextColumn() {
+		return
+	}
+	p.newPage()
+}
+
+// newPage starts a new page in the size of the current one. (fpdf's AddPage starts a page in the document's
+// default format.)
+func (p *Processor) newPage() {
+	p.firstColumn()
+	w, h := p.pdf.GetPageSize()
+	p.pdf.AddPageFormat("P", gofpdf.SizeType{Wd: w, Ht: h})
+}
@@ -0,0 +1,56 @@
+package gompdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPageBreakMargins(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<text>first</text>
+<page-break margins="20,30,25,40"/>
+<text>second</text>
+</body>
+</document>`)
+	if p.pdf.PageNo() != 2 {
+		t.Errorf("want 2 pages, got %d", p.pdf.PageNo())
+	}
+	l, top, r, b := p.pdf.GetMargins()
+	if l != 20 || top != 30 || r != 25 || b != 40 {
+		t.Errorf("want margins 20,30,25,40, got %v,%v,%v,%v", l, top, r, b)
+	}
+	if p.pageMargins != (PageMargins{Left: 20, Top: 30, Right: 25, Bottom: 40}) {
+		t.Errorf("want page margins 20,30,25,40, got %v", p.pageMargins)
+	}
+}
+
+func TestPageBreakMarginsInColumns(t *testing.T) {
+	src := `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<columns count="2"><text>first</text><page-break margins="20,30,25,40"/><text>second</text></columns>
+<text>after</text>
+</body>
+</document>`
+	p := loadTestProcessor(t, src)
+	l, top, r, b := p.pdf.GetMargins()
+	if l != 0 || top != 0 || r != 0 || b != 0 || p.pageMargins != (PageMargins{}) {
+		t.Errorf("want the margins of the page break ignored, got %v,%v,%v,%v", l, top, r, b)
+	}
+
+	doc, err := Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err = NewProcessor(doc, WithStrict())
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	err = p.Process(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "page margins (20,30,25,40) of a page break within columns are ignored") {
+		t.Errorf("want error for the ignored margins in strict mode, got %v", err)
+	}
+}
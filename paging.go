@@ -0,0 +1,175 @@
+package gompdf
+
+import (
+	"math"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+// pageBottom returns the y, where pages break, and whether page breaks are controlled. They aren't without
+// automatic page breaks and within table cells.
+func (p *Processor) pageBottom() (float64, bool) {
+	auto, margin := p.pdf.GetAutoPageBreak()
+	_, ph := p.pdf.GetPageSize()
+	return ph - margin, auto && !p.inCell
+}
+
+// flowTop returns where the flow starts on the current page, which is the top of the columns within columns.
+func (p *Processor) flowTop() float64 {
+	if p.columns != nil && !p.inCell {
+		return p.columns.top
+	}
+	return p.pageTopY
+}
+
+// avoidBreak starts a new page, if height doesn't fit on the current page (or column), but on an empty one.
+func (p *Processor) avoidBreak(height float64) {
+	bottom, ok := p.pageBottom()
+	if !ok {
+		return
+	}
+	y, top := p.pdf.GetY(), p.flowTop()
+	if y > top && y+height > bottom && top+height <= bottom {
+		p.addPage()
+	}
+}
+
+// linePageBreaks returns the indices of the lines starting a new page, when n lines are written at the current
+// position, following the page-break-inside, orphans and widows styles. Without these it returns nil and pages
+// break automatically.
+func (p *Processor) linePageBreaks(n int, lineHeight float64, sty style.Styles) []int {
+	bottom, ok := p.pageBottom()
+	avoid := sty.Paging.PageBreakInside == style.PageBreakInsideAvoid
+	if !ok || n == 0 || lineHeight <= 0 || (!avoid && sty.Paging.Orphans <= 1 && sty.Paging.Widows <= 1) {
+		return nil
+	}
+	top := p.flowTop()
+	fits := func(y float64) int {
+		return int(math.Max(0, math.Floor((bottom-y)/lineHeight+0.001)))
+	}
+	perPage := fits(top)
+	if perPage < 1 {
+		return nil
+	}
+	y := p.pdf.GetY()
+	if avoid && n <= perPage {
+		if fits(y) < n && y > top {
+			return []int{0}
+		}
+		return nil
+	}
+
+	orphans, widows := maxInt(sty.Paging.Orphans, 1), maxInt(sty.Paging.Widows, 1)
+	breaks := []int{}
+	for pos := 0; n-pos > fits(y); {
+		k := fits(y)
+		if n-pos-k < widows {
+			k = n - pos - widows
+		}
+		if k < orphans {
+			k = 0
+		}
+		if k == 0 && y <= top {
+			//the rules can't be met on an empty page
+			k = maxInt(fits(y), 1)
+		}
+		pos += k
+		breaks = append(breaks, pos)
+		y = top
+	}
+	return breaks
+}
+
+// breakBeforeText starts a new page, if the text written at the current position starts on the next page.
+func (p *Processor) breakBeforeText(text string, width float64, sty style.Styles) {
+	n := nonEmptyLines(p.textLines(p.textItems(text), width, sty))
+	breaks := p.linePageBreaks(n, p.lineHeight(sty), sty)
+	if len(breaks) > 0 && breaks[0] == 0 {
+		p.addPage()
+	}
+}
+
+// keepWithNextHeight returns the height, which has to fit on the current page, so that the block is is[k] stays
+// with the next block. It is 0, if the instruction isn't a block with keep-with-next.
+func (p *Processor) keepWithNextHeight(is []Instruction, k int) float64 {
+	m, block := p.blockMargin(is[k])
+	if !block || !p.appliedStyles(is[k]).Paging.KeepWithNext {
+		return 0
+	}
+	saved := p.currStyles
+	defer func() {
+		p.currStyles = saved
+		p.applyFont(saved.Font)
+	}()
+
+	height := m.Top + p.blockHeight(is[k], m) + m.Bottom
+	//floats following the block have to fit as well
+	floatBottom := height
+	for _, i := range is[k+1:] {
+		if p.clearsFloats(i) {
+			height = math.Max(height, floatBottom)
+		}
+		m, block := p.blockMargin(i)
+		if !block {
+			floatBottom = math.Max(floatBottom, height+p.floatHeight(i))
+			height += p.instructionHeight(i)
+			continue
+		}
+		sty := p.appliedStyles(i)
+		if sty.Paging.KeepWithNext {
+			height += m.Top + p.blockHeight(i, m) + m.Bottom
+			continue
+		}
+		height += m.Top
+		p.withHorizontalMargin(m, func() {
+			height += p.leadHeight(i, sty)
+		})
+		break
+	}
+	return math.Max(height, floatBottom)
+}
+
+// blockHeight returns the height of a block without its vertical margins.
+func (p *Processor) blockHeight(i Instruction, m style.Margin) float64 {
+	var height float64
+	p.withHorizontalMargin(m, func() {
+		height = p.instructionHeight(i)
+	})
+	return height
+}
+
+// leadHeight returns the height of the start of a block, which can't be separated by a page break. For text these
+// are the first lines kept together by orphans, for other blocks the whole block.
+func (p *Processor) leadHeight(i Instruction, sty style.Styles) float64 {
+	var text string
+	switch i := i.(type) {
+	case *Text:
+		text = i.Text
+	case *Heading:
+		text = i.Text
+	default:
+		return p.instructionHeight(i)
+	}
+	n := nonEmptyLines(p.textLines(p.textItems(text), p.effectiveWidth(sty.Dimension.Width), sty))
+	if sty.Paging.PageBreakInside != style.PageBreakInsideAvoid && n > maxInt(sty.Paging.Orphans, 1) {
+		n = maxInt(sty.Paging.Orphans, 1)
+	}
+	return float64(n) * p.lineHeight(sty)
+}
+
+func nonEmptyLines(lines []textLine) int {
+	n := 0
+	for _, line := range lines {
+		if line.occupied() {
+			n++
+		}
+	}
+	return n
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,65 @@
+package gompdf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+func TestLinePageBreaks(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body></body>
+</document>`)
+	//21 lines of height 10 fit on an a5 page without margins
+	if bottom, ok := p.pageBottom(); !ok || math.Abs(bottom-210) > 0.01 || p.flowTop() != 0 {
+		t.Fatalf("want page from 0 to 210 with page breaks, got %v to %v (%t)", p.flowTop(), bottom, ok)
+	}
+	paging := func(avoid bool, orphans, widows int) style.Styles {
+		sty := p.currStyles
+		sty.Paging.PageBreakInside = style.PageBreakInsideAuto
+		if avoid {
+			sty.Paging.PageBreakInside = style.PageBreakInsideAvoid
+		}
+		sty.Paging.Orphans, sty.Paging.Widows = orphans, widows
+		return sty
+	}
+	tests := []struct {
+		name   string
+		y      float64
+		n      int
+		sty    style.Styles
+		breaks []int
+	}{
+		{name: "no rules", y: 200, n: 10, sty: paging(false, 0, 0), breaks: nil},
+		{name: "exact fit", y: 150, n: 6, sty: paging(false, 2, 2), breaks: []int{}},
+		{name: "exact fit of the next page", y: 150, n: 27, sty: paging(false, 2, 2), breaks: []int{6}},
+		{name: "several pages", y: 150, n: 50, sty: paging(false, 2, 2), breaks: []int{6, 27, 48}},
+		{name: "too few lines for orphans", y: 190, n: 10, sty: paging(false, 3, 1), breaks: []int{0}},
+		{name: "enough lines for orphans", y: 180, n: 10, sty: paging(false, 3, 1), breaks: []int{3}},
+		{name: "too few lines for widows", y: 150, n: 8, sty: paging(false, 1, 3), breaks: []int{5}},
+		{name: "too few lines for widows and orphans", y: 180, n: 5, sty: paging(false, 3, 3), breaks: []int{0}},
+		{name: "fewer lines than widows", y: 200, n: 2, sty: paging(false, 1, 3), breaks: []int{0}},
+		{name: "orphans on an empty page", y: 0, n: 40, sty: paging(false, 30, 1), breaks: []int{21}},
+		{name: "avoid fitting", y: 100, n: 11, sty: paging(true, 0, 0), breaks: nil},
+		{name: "avoid on the next page", y: 150, n: 10, sty: paging(true, 0, 0), breaks: []int{0}},
+		{name: "avoid on an empty page", y: 0, n: 21, sty: paging(true, 0, 0), breaks: nil},
+		{name: "avoid taller than a page", y: 150, n: 30, sty: paging(true, 0, 0), breaks: []int{6, 27}},
+		{name: "avoid taller than a page with widows", y: 150, n: 29, sty: paging(true, 1, 3), breaks: []int{6, 26}},
+	}
+	for _, test := range tests {
+		p.pdf.SetY(test.y)
+		breaks := p.linePageBreaks(test.n, 10, test.sty)
+		if !reflect.DeepEqual(breaks, test.breaks) {
+			t.Errorf("%s: want breaks %v, got %v", test.name, test.breaks, breaks)
+		}
+	}
+
+	p.inCell = true
+	p.pdf.SetY(200)
+	if breaks := p.linePageBreaks(10, 10, paging(true, 3, 3)); breaks != nil {
+		t.Errorf("want no breaks in table cells, got %v", breaks)
+	}
+}
@@ -3,11 +3,15 @@ package gompdf
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/jung-kurt/gofpdf/v2"
 	"github.com/mazzegi/gompdf/style"
+	"github.com/pkg/errors"
 )
 
 type ProcessOption func(p *Processor) error
@@ -18,10 +22,58 @@ type Processor struct {
 
 	fontDir  string
 	codePage string
+	fonts    []fontRegistration
 
-	transformText func(string) string
+	translateUnicode func(string) string
+	//utf8Fonts maps the (lower case) families of embedded UTF-8 fonts to their registered fpdf styles
+	utf8Fonts map[string]map[string]bool
 
-	currStyles style.Styles
+	currStyles    style.Styles
+	bookmarkLevel int
+
+	//toc holds the entries collected in the previous layout pass, tocCollected those of the current pass
+	toc          []tocEntry
+	tocCollected []tocEntry
+	tocRendered  bool
+
+	//links maps anchor names to fpdf links, anchors holds the names of the anchors set in the current pass
+	links   map[string]int
+	anchors map[string]bool
+
+	//sections and pageCount hold the layout of the previous pass, sectionsCollected those of the current pass
+	sections          []section
+	sectionsCollected []section
+	sectionsUsed      bool
+	pageCount         int
+	pageTopY          float64
+	//generated is the time of processing, which replaces {date} and {time}
+	generated time.Time
+	//overflowBottom limits the text of boxes and cells with fixed height, if set
+	overflowBottom float64
+	strict         bool
+	//stylesChecked is set, when the problems of the document's styles are reported in the first layout pass
+	stylesChecked bool
+	lastMargin    marginEnd
+	//inCell is set while laying out the content of table cells, where pages don't break
+	inCell bool
+	//frames is the number of frames (see withFrame) the flow is laid out in
+	frames int
+	//pageMargins are the margins of the current page, columns the flow of the columns container being laid out
+	pageMargins PageMargins
+	columns     *columnFlow
+	//floats are the areas of floating images, which text wraps around
+	floats []floatArea
+	//images holds the data of registered and resolved image sources
+	images         map[string][]byte
+	imageResolvers []ImageResolver
+}
+
+type fontRegistration struct {
+	family string
+	style  string
+	file   string
+	//data is the content of file, which is read once for all layout passes
+	data []byte
 }
 
 func WithFontDir(dir string) ProcessOption {
@@ -38,12 +90,26 @@ func WithCodePage(cp string) ProcessOption {
 	}
 }
 
+// WithFont registers a TrueType font file with UTF-8 support for family. fontStyle is the fpdf style
+// of the file ("", "B", "I" or "BI"). Relative files are looked up in the font dir first.
+func WithFont(family, fontStyle, file string) ProcessOption {
+	return func(p *Processor) error {
+		p.fonts = append(p.fonts, fontRegistration{
+			family: family,
+			style:  fontStyle,
+			file:   file,
+		})
+		return nil
+	}
+}
+
 func NewProcessor(doc *Document, options ...ProcessOption) (*Processor, error) {
 	p := &Processor{
 		doc:        doc,
 		fontDir:    "fonts",
 		codePage:   "",
 		currStyles: DefaultStyle,
+		images:     map[string][]byte{},
 	}
 	for _, o := range options {
 		err := o(p)
@@ -51,13 +117,64 @@ func NewProcessor(doc *Document, options ...ProcessOption) (*Processor, error) {
 			return nil, err
 		}
 	}
-
+	err := p.loadFonts()
+	if err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
+// maxLayoutPasses limits the passes needed to settle the page numbers of a table of contents.
+const maxLayoutPasses = 4
+
 func (p *Processor) Process(w io.Writer) error {
+	if p.strict {
+		err := checkStrict(p.doc)
+		if err != nil {
+			return err
+		}
+	}
+	//a table of contents and section page numbers need the final page numbers, so the document is laid out
+	//again with the page numbers of the previous pass until they don't change anymore.
 	start := time.Now()
 	fmt.Printf("run instructions ...\n")
+	p.toc = nil
+	p.sections = nil
+	p.pageCount = 0
+	p.stylesChecked = false
+	p.generated = time.Now()
+	for pass := 1; ; pass++ {
+		err := p.layout()
+		if err != nil {
+			return err
+		}
+		if p.settled() {
+			break
+		}
+		if pass >= maxLayoutPasses {
+			p.problem(errors.Errorf("page numbers did not settle after %d passes", pass))
+			break
+		}
+		p.toc = p.tocCollected
+		p.sections = p.sectionsCollected
+		p.pageCount = p.pdf.PageNo()
+	}
+	fmt.Printf("run instructions ... in (%s)\n", time.Since(start))
+	return p.pdf.Output(w)
+}
+
+// settled reports whether the page numbers used in the current pass are the final ones.
+func (p *Processor) settled() bool {
+	if p.tocRendered && !tocEntriesEqual(p.toc, p.tocCollected) {
+		return false
+	}
+	if p.sectionsUsed && (!sectionsEqual(p.sections, p.sectionsCollected) || p.pageCount != p.pdf.PageNo()) {
+		return false
+	}
+	return true
+}
+
+func (p *Processor) layout() error {
 	p.pdf = gofpdf.New(
 		fpdfOrientation(p.doc.Default.Orientation),
 		fpdfUnit(p.doc.Default.Unit),
@@ -66,35 +183,182 @@ func (p *Processor) Process(w io.Writer) error {
 	)
 
 	p.pdf.AliasNbPages("{np}")
-	translateUnicode := p.pdf.UnicodeTranslatorFromDescriptor(p.codePage)
-	p.transformText = func(s string) string {
-		ts := strings.Replace(s, "{cp}", fmt.Sprintf("%d", p.pdf.PageNo()), -1)
-		return translateUnicode(ts)
+	p.pdf.SetAuthor(p.doc.Meta.Author, true)
+	p.pdf.SetCreator(p.doc.Meta.Creator, true)
+	p.pdf.SetSubject(p.doc.Meta.Subject, true)
+	p.translateUnicode = p.pdf.UnicodeTranslatorFromDescriptor(p.codePage)
+	err := p.registerFonts()
+	if err != nil {
+		return err
+	}
+	if !p.stylesChecked {
+		p.stylesChecked = true
+		for _, err := range p.doc.styleProblems() {
+			p.problem(err)
+		}
 	}
 
 	p.pdf.SetHeaderFunc(func() {
-		p.processInstructions(p.doc.Header)
+		p.withPageFrame(func() { p.processInstructions(p.doc.Header) })
+		p.pageTopY = p.pdf.GetY()
+		p.startColumnsPage()
 	})
 	p.pdf.SetFooterFunc(func() {
-		p.processInstructions(p.doc.Footer)
+		p.withPageFrame(func() { p.processInstructions(p.doc.Footer) })
 	})
+	p.pdf.SetAcceptPageBreakFunc(p.acceptPageBreak)
+	p.currStyles = DefaultStyle
 	p.applyDefaults()
 	p.applyFont(p.currStyles.Font)
+	p.bookmarkLevel = -1
+	p.tocCollected = nil
+	p.tocRendered = false
+	p.links = map[string]int{}
+	p.anchors = map[string]bool{}
+	p.sectionsCollected = nil
+	p.lastMargin = marginEnd{}
+	p.sectionsUsed = false
+	p.columns = nil
+	p.floats = nil
 
 	p.pdf.AddPage()
 	p.processInstructions(p.doc.Body)
+	p.resolveLinks()
 
-	err := p.pdf.Error()
-	if err != nil {
-		return err
+	return p.pdf.Error()
+}
+
+// loadFonts reads the font files of the document and of WithFont options once, before the layout passes.
+func (p *Processor) loadFonts() error {
+	regs := []fontRegistration{}
+	for _, fs := range p.doc.Fonts {
+		regs = append(regs, fontRegistration{
+			family: fs.Family,
+			style:  fpdfFontStyle(style.Font{Style: fs.Style, Weight: fs.Weight}),
+			file:   fs.File,
+		})
 	}
-	fmt.Printf("run instructions ... in (%s)\n", time.Since(start))
-	return p.pdf.Output(w)
+	regs = append(regs, p.fonts...)
+	for i, reg := range regs {
+		if reg.family == "" {
+			return errors.Errorf("font (%s) without family", reg.file)
+		}
+		b, err := ioutil.ReadFile(p.fontFile(reg.file))
+		if err != nil {
+			return errors.Wrapf(err, "read font (%s)", reg.file)
+		}
+		regs[i].data = b
+	}
+	p.fonts = regs
+	return nil
+}
+
+func (p *Processor) registerFonts() error {
+	p.utf8Fonts = map[string]map[string]bool{}
+	for _, reg := range p.fonts {
+		p.pdf.AddUTF8FontFromBytes(reg.family, reg.style, reg.data)
+		family := strings.ToLower(reg.family)
+		if _, ok := p.utf8Fonts[family]; !ok {
+			p.utf8Fonts[family] = map[string]bool{}
+		}
+		p.utf8Fonts[family][normalizedFontStyle(reg.style)] = true
+	}
+	return p.pdf.Error()
+}
+
+func (p *Processor) fontFile(file string) string {
+	if filepath.IsAbs(file) || p.fontDir == "" {
+		return file
+	}
+	inDir := filepath.Join(p.fontDir, file)
+	if _, err := os.Stat(inDir); err == nil {
+		return inDir
+	}
+	return file
+}
+
+// normalizedFontStyle returns the fpdf style without underline in the order fpdf uses for font keys.
+func normalizedFontStyle(fpdfStyle string) string {
+	s := strings.ToUpper(strings.Replace(fpdfStyle, "U", "", -1))
+	bold, italic := strings.Contains(s, "B"), strings.Contains(s, "I")
+	switch {
+	case bold && italic:
+		return "BI"
+	case bold:
+		return "B"
+	case italic:
+		return "I"
+	}
+	return ""
+}
+
+// setFont sets the font like fpdf does. For embedded UTF-8 fonts a missing bold or italic variant
+// falls back to a registered one, instead of failing with an undefined font.
+func (p *Processor) setFont(family, fpdfStyle string, size float64) {
+	if styles, ok := p.utf8Fonts[strings.ToLower(family)]; ok {
+		underline := strings.Contains(strings.ToUpper(fpdfStyle), "U")
+		s := normalizedFontStyle(fpdfStyle)
+		if !styles[s] {
+			for _, fallback := range []string{strings.Replace(s, "I", "", -1), strings.Replace(s, "B", "", -1), "", "B", "I", "BI"} {
+				if styles[fallback] {
+					s = fallback
+					break
+				}
+			}
+		}
+		if underline {
+			s += "U"
+		}
+		fpdfStyle = s
+	}
+	p.pdf.SetFont(family, fpdfStyle, size)
+}
+
+// textTransformer returns the function, which prepares text for writing in fnt.
+func (p *Processor) textTransformer(fnt style.Font) func(string) string {
+	enc := p.encoder(fnt)
+	return func(s string) string {
+		return enc(p.replacePlaceholders(s))
+	}
+}
+
+// encoder returns the function, which encodes text for fnt. Text for embedded UTF-8 fonts is written as is,
+// text for core fonts is translated to the code page.
+func (p *Processor) encoder(fnt style.Font) func(string) string {
+	if _, ok := p.utf8Fonts[strings.ToLower(fnt.Family)]; ok {
+		return func(s string) string { return s }
+	}
+	return p.translateUnicode
+}
+
+// replacePlaceholders replaces the page, section, time and meta placeholders in s. The total page count {np}
+// is replaced by fpdf when the document is written.
+func (p *Processor) replacePlaceholders(s string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	if strings.Contains(s, "{sp}") || strings.Contains(s, "{snp}") || strings.Contains(s, "{section}") {
+		sec, page, pages := p.currentSection()
+		s = strings.NewReplacer(
+			"{sp}", fmt.Sprintf("%d", page),
+			"{snp}", fmt.Sprintf("%d", pages),
+			"{section}", sec.title,
+		).Replace(s)
+	}
+	return strings.NewReplacer(
+		"{cp}", fmt.Sprintf("%d", p.pdf.PageNo()),
+		"{date}", p.generated.Format("2006-01-02"),
+		"{time}", p.generated.Format("15:04"),
+		"{author}", p.doc.Meta.Author,
+		"{creator}", p.doc.Meta.Creator,
+		"{subject}", p.doc.Meta.Subject,
+	).Replace(s)
 }
 
 func (p *Processor) applyDefaults() {
 	p.pdf.SetAutoPageBreak(p.doc.Default.PageBreaks == PageBreakModeAuto, p.doc.Default.PageMargins.Bottom)
 	p.pdf.SetMargins(p.doc.Default.PageMargins.Left, p.doc.Default.PageMargins.Top, p.doc.Default.PageMargins.Right)
+	p.pageMargins = p.doc.Default.PageMargins
 }
 
 func (p *Processor) appliedStyles(i Instruction) style.Styles {
@@ -104,7 +368,13 @@ func (p *Processor) appliedStyles(i Instruction) style.Styles {
 }
 
 func (p *Processor) processInstructions(is Instructions) {
-	for _, i := range is.iss {
+	for k, i := range is.iss {
+		if height := p.keepWithNextHeight(is.iss, k); height > 0 {
+			p.avoidBreak(height)
+		}
+		if p.clearsFloats(i) {
+			p.clearFloats()
+		}
 		switch i := i.(type) {
 		case *Font:
 			i.Apply(p.doc.styleClasses, &p.currStyles)
@@ -118,13 +388,44 @@ func (p *Processor) processInstructions(is Instructions) {
 		case *SetXY:
 			p.pdf.SetXY(i.X, i.Y)
 		case *Box:
-			p.renderTextBox(i.Text, p.appliedStyles(i))
+			sty := p.appliedStyles(i)
+			p.withMargin(sty.Box.Margin, func() { p.renderTextBox(i, sty) })
 		case *Text:
-			p.renderText(i, p.appliedStyles(i))
+			sty := p.appliedStyles(i)
+			p.withMargin(sty.Box.Margin, func() { p.renderText(i, sty) })
+		case *Heading:
+			sty := p.appliedStyles(i)
+			p.withMargin(sty.Box.Margin, func() { p.renderHeading(i, sty) })
+		case *Toc:
+			p.renderToc(i, p.appliedStyles(i))
+		case *Link:
+			p.renderLink(i, p.appliedStyles(i))
+		case *Anchor:
+			p.setAnchor(i.Name, p.pdf.GetY())
+		case *Section:
+			p.startSection(i)
+		case *PageBreak:
+			if !p.inCell {
+				p.processPageBreak(i)
+			}
 		case *Table:
-			p.renderTable(i, p.appliedStyles(i))
+			sty := p.appliedStyles(i)
+			p.withMargin(sty.Box.Margin, func() { p.renderTable(i, sty) })
 		case *Image:
-			p.renderImage(i, p.appliedStyles(i))
+			sty := p.appliedStyles(i)
+			if p.floating(i, sty) {
+				p.renderFloatImage(i, sty)
+				break
+			}
+			p.withMargin(sty.Box.Margin, func() { p.renderImage(i, sty) })
+		case *Barcode:
+			p.renderBarcode(i, p.appliedStyles(i))
+		case shape:
+			p.renderShape(i, p.appliedStyles(i))
+		case *Columns:
+			p.renderColumns(i, p.appliedStyles(i))
+		default:
+			p.renderCustom(i, p.appliedStyles(i))
 		}
 	}
 }
@@ -139,7 +440,39 @@ func (p *Processor) effectiveWidth(width float64) float64 {
 }
 
 func (p *Processor) applyFont(fnt style.Font) {
-	p.pdf.SetFont(string(fnt.Family), fpdfFontStyle(fnt), float64(fnt.PointSize))
+	p.setFont(string(fnt.Family), fpdfFontStyle(fnt), float64(fnt.PointSize))
+}
+
+// resetStyles re-applies font and text color of the current styles after rendering with other styles.
+func (p *Processor) resetStyles() {
+	p.pdf.SetTextColor(int(p.currStyles.Color.Text.R), int(p.currStyles.Color.Text.G), int(p.currStyles.Color.Text.B))
+	p.applyFont(p.currStyles.Font)
+}
+
+// withFrame runs fn with the page margins narrowed to the horizontal range [x, x+width], so that
+// line feeds return to x and effectiveWidth refers to the frame.
+// If fn moves on to another column, the restored frame moves along.
+func (p *Processor) withFrame(x, width float64, fn func()) {
+	l, t, r, _ := p.pdf.GetMargins()
+	pw, _ := p.pdf.GetPageSize()
+	shift := p.columnShift()
+	p.pdf.SetLeftMargin(x)
+	p.pdf.SetRightMargin(pw - x - width)
+	p.frames++
+	fn()
+	p.frames--
+	dx := p.columnShift() - shift
+	p.pdf.SetMargins(l+dx, t, r-dx)
+}
+
+// withPageFrame runs fn with the margins of the page, e.g. for headers and footers of pages started within a frame.
+func (p *Processor) withPageFrame(fn func()) {
+	l, t, r, _ := p.pdf.GetMargins()
+	p.pdf.SetMargins(p.pageMargins.Left, t, p.pageMargins.Right)
+	p.pdf.SetX(p.pageMargins.Left)
+	fn()
+	p.pdf.SetMargins(l, t, r)
+	p.pdf.SetX(l)
 }
 
 func (p *Processor) processLineFeed(lf *LineFeed, sty style.Styles) {
@@ -148,34 +481,56 @@ func (p *Processor) processLineFeed(lf *LineFeed, sty style.Styles) {
 	p.pdf.Ln(height)
 }
 
+// withOverflowBottom runs fn with the overflow bottom set to bottom, if fixed is set, or unset otherwise.
+func (p *Processor) withOverflowBottom(fixed bool, bottom float64, fn func()) {
+	saved := p.overflowBottom
+	p.overflowBottom = 0
+	if fixed {
+		p.overflowBottom = bottom
+	}
+	fn()
+	p.overflowBottom = saved
+}
+
+// ensureSpace starts a new page, if height doesn't fit on the current page anymore.
+// ensureSpace starts a new page, if height doesn't fit on the current page anymore. In table cells the row and
+// table decide on page breaks.
+func (p *Processor) ensureSpace(height float64) {
+	if p.inCell {
+		return
+	}
+	_, ph := p.pdf.GetPageSize()
+	_, _, _, bottomM := p.pdf.GetMargins()
+	if p.pdf.GetY()+height > ph-bottomM {
+		p.addPage()
+	}
+}
+
 func (p *Processor) renderText(text *Text, sty style.Styles) {
-	p.write(text.Text, p.effectiveWidth(sty.Dimension.Width), sty.Dimension.LineHeight, sty.Align.HAlign, sty.Font, sty.Color.Text)
+	p.breakBeforeText(text.Text, p.effectiveWidth(sty.Dimension.Width), sty)
+	if text.Outline.isSet() {
+		p.ensureSpace(p.lineHeight(sty))
+		p.markOutline(text.Outline, text.Text, sty.Font, p.pdf.GetY())
+	}
+	p.write(text.Text, p.effectiveWidth(sty.Dimension.Width), sty)
 }
 
-func (p *Processor) renderTextBox(text string, sty style.Styles) {
+func (p *Processor) renderTextBox(box *Box, sty style.Styles) {
+	text := box.Text
 	width := p.effectiveWidth(sty.Dimension.Width)
 	textWidth := width - sty.Box.Padding.Left - sty.Box.Padding.Right - 3 //without -2 it writes over the border
-
-	var height float64
-	if sty.Dimension.Height < 0 {
-		if text != "" {
-			height = p.textHeight(text, textWidth, sty.Dimension.LineHeight, sty.Font)
-		} else {
-			height = p.textHeight("Üg", textWidth, sty.Dimension.LineHeight, sty.Font)
-		}
-	} else {
-		height = sty.Dimension.Height
-	}
+	height := p.textBoxHeight(text, sty)
 
 	x0, y0 := p.pdf.GetXY()
 	_, ph := p.pdf.GetPageSize()
 	if y0+height >= ph {
-		p.pdf.AddPage()
+		p.addPage()
 		x0, y0 = p.pdf.GetXY()
 	}
 
 	x0 += sty.Dimension.OffsetX
 	y0 += sty.Dimension.OffsetY
+	p.markOutline(box.Outline, box.Text, sty.Font, y0)
 	y1 := y0 + height + sty.Box.Padding.Top + sty.Box.Padding.Bottom
 	x1 := x0 + width
 	p.drawBox(x0, y0, x1, y1, sty)
@@ -183,13 +538,25 @@ func (p *Processor) renderTextBox(text string, sty style.Styles) {
 	//Reset, to start writing at top left
 	p.pdf.SetY(y0 + sty.Box.Padding.Top)
 	p.pdf.SetX(x0 + sty.Box.Padding.Left)
-	p.write(text, textWidth, sty.Dimension.LineHeight, sty.Align.HAlign, sty.Font, sty.Color.Text)
-	p.pdf.Ln(sty.Dimension.LineHeight + sty.Box.Padding.Bottom)
+	p.withOverflowBottom(sty.Dimension.Height >= 0, y1-sty.Box.Padding.Bottom, func() {
+		p.write(text, textWidth, sty)
+	})
+	p.pdf.SetY(y1)
+	p.pdf.Ln(sty.Dimension.LineHeight)
 }
 
 func (p *Processor) renderImage(img *Image, sty style.Styles) {
+	info := p.registerImage(img.Source)
+	if info == nil {
+		return
+	}
+	w, h := p.imageSize(info, sty)
+	if sty.Paging.PageBreakInside == style.PageBreakInsideAvoid {
+		p.avoidBreak(sty.Dimension.OffsetY + h)
+	}
 	x0, y0 := p.pdf.GetXY()
 	x0 += sty.Dimension.OffsetX
 	y0 += sty.Dimension.OffsetY
-	p.pdf.ImageOptions(img.Source, x0, y0, sty.Dimension.Width, sty.Dimension.Height, false, gofpdf.ImageOptions{}, 0, "")
+	p.pdf.ImageOptions(img.Source, x0, y0, w, h, false, gofpdf.ImageOptions{}, 0, "")
+	p.pdf.SetY(y0 + h)
 }
@@ -0,0 +1,104 @@
+package gompdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+// loadTestProcessor returns a processor for the document source, laid out once, so that its pdf is ready for
+// measuring.
+func loadTestProcessor(t *testing.T, src string, options ...ProcessOption) *Processor {
+	t.Helper()
+	doc, err := Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := NewProcessor(doc, options...)
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	err = p.layout()
+	if err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	return p
+}
+
+func processTestSource(t *testing.T, src string, options ...ProcessOption) []byte {
+	t.Helper()
+	doc, err := Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := NewProcessor(doc, options...)
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	err = p.Process(buf)
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessBoxWithoutTextWidth(t *testing.T) {
+	processTestSource(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body><box style="width:4;padding:3,3,3,3">hello world</box></body>
+</document>`)
+}
+
+// alternatingPages adds a page in every other layout pass, so that the page numbers never settle.
+type alternatingPages struct {
+	NoStyles
+	XMLName xml.Name `xml:"test-alternating-pages"`
+}
+
+var alternatingPagesRendered int
+
+func (a *alternatingPages) Render(ctx *Context, sty style.Styles) error {
+	alternatingPagesRendered++
+	if alternatingPagesRendered%2 == 1 {
+		ctx.PDF().AddPage()
+	}
+	return nil
+}
+
+var registerAlternatingPages sync.Once
+
+func TestProcessUnsettledPageNumbers(t *testing.T) {
+	registerAlternatingPages.Do(func() {
+		err := RegisterInstruction(&alternatingPages{})
+		if err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	})
+	src := `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body><section title="one"/><text>page {sp} of {snp}</text><test-alternating-pages/></body>
+</document>`
+	alternatingPagesRendered = 0
+	processTestSource(t, src)
+	if alternatingPagesRendered != maxLayoutPasses {
+		t.Errorf("want %d layout passes, got %d", maxLayoutPasses, alternatingPagesRendered)
+	}
+
+	doc, err := Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := NewProcessor(doc, WithStrict())
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	err = p.Process(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "page numbers did not settle after 4 passes") {
+		t.Errorf("want error for unsettled page numbers in strict mode, got %v", err)
+	}
+}
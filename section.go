@@ -0,0 +1,61 @@
+package gompdf
+
+import (
+	"encoding/xml"
+)
+
+// Section starts a new section on a new page, unless the current page is still empty. Sections restart
+// the section page numbering ({sp} of {snp}) and their title replaces {section}.
+type Section struct {
+	NoStyles
+	XMLName xml.Name `xml:"section"`
+	Title   string   `xml:"title,attr"`
+}
+
+type section struct {
+	title     string
+	firstPage int
+}
+
+func sectionsEqual(ss1, ss2 []section) bool {
+	if len(ss1) != len(ss2) {
+		return false
+	}
+	for i := range ss1 {
+		if ss1[i] != ss2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Processor) startSection(s *Section) {
+	if p.pdf.GetY() > p.pageTopY {
+		p.addPage()
+	}
+	p.sectionsCollected = append(p.sectionsCollected, section{
+		title:     s.Title,
+		firstPage: p.pdf.PageNo(),
+	})
+}
+
+// currentSection returns the section of the current page, its page number within the section and the
+// section's page count, as laid out in the previous pass. Pages before the first section form an untitled one.
+func (p *Processor) currentSection() (section, int, int) {
+	p.sectionsUsed = true
+	page := p.pdf.PageNo()
+	curr := section{firstPage: 1}
+	last := p.pageCount
+	for _, s := range p.sections {
+		if s.firstPage > page {
+			last = s.firstPage - 1
+			break
+		}
+		curr = s
+	}
+	if last < page {
+		//not laid out yet
+		last = page
+	}
+	return curr, page - curr.firstPage + 1, last - curr.firstPage + 1
+}
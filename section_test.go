@@ -0,0 +1,68 @@
+package gompdf
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var textShowRx = regexp.MustCompile(`\((.*?)\)Tj`)
+
+// writtenText returns the texts written in the content one after the other.
+func writtenText(content string) string {
+	s := ""
+	for _, m := range textShowRx.FindAllStringSubmatch(content, -1) {
+		s += m[1]
+	}
+	return s
+}
+
+func TestSectionPlaceholders(t *testing.T) {
+	content := pdfContent(t, processTestSource(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<text>intro [{section}] {sp}/{snp} {cp}/{np}.</text>
+<section title="Alpha"/>
+<text>alpha [{section}] {sp}/{snp} {cp}/{np}.</text>
+<page-break/>
+<text>more [{section}] {sp}/{snp} {cp}/{np}.</text>
+<section title="Beta"/>
+<text>beta [{section}] {sp}/{snp} {cp}/{np}.</text>
+<page-break/>
+<section title="Gamma"/>
+<text>gamma [{section}] {sp}/{snp} {cp}/{np}.</text>
+</body>
+</document>`))
+	written := writtenText(content)
+	for _, s := range []string{
+		//pages before the first section form an untitled one
+		"intro [] 1/1 1/5.",
+		//sections start on a new page and number their pages, counted in the previous layout pass
+		"alpha [Alpha] 1/2 2/5.",
+		"more [Alpha] 2/2 3/5.",
+		"beta [Beta] 1/1 4/5.",
+		//on an empty page the section starts on the current page
+		"gamma [Gamma] 1/1 5/5.",
+	} {
+		if !strings.Contains(written, s) {
+			t.Errorf("want (%s) written, got (%s)", s, written)
+		}
+	}
+}
+
+func TestSectionPlaceholdersWithoutSections(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body><text>first</text><page-break/><text>second</text></body>
+</document>`)
+	p.pageCount = 2
+	if s := p.replacePlaceholders("[{section}] {sp}/{snp} {cp}"); s != "[] 2/2 2" {
+		t.Errorf("want the pages numbered in an untitled section, got (%s)", s)
+	}
+	if !p.sectionsUsed {
+		t.Errorf("want the use of section placeholders recorded")
+	}
+	if s := p.replacePlaceholders("no placeholders"); s != "no placeholders" {
+		t.Errorf("want text without placeholders unchanged, got (%s)", s)
+	}
+}
@@ -0,0 +1,373 @@
+package gompdf
+
+import (
+	"encoding/xml"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mazzegi/gompdf/style"
+	"github.com/pkg/errors"
+)
+
+type ShapePosition string
+
+const (
+	ShapePositionRelative ShapePosition = "relative"
+	ShapePositionAbsolute ShapePosition = "absolute"
+)
+
+// Shape holds the attributes common to the drawing instructions. Coordinates are relative to the cursor, or to the
+// top left corner of the page, if the position is absolute. Relative shapes move the cursor below them, absolute
+// ones leave it untouched. Outlines are drawn in color with line-width, closed shapes are filled with
+// background-color, if Fill is set.
+type Shape struct {
+	Position ShapePosition `xml:"position,attr"`
+	Fill     bool          `xml:"fill,attr"`
+}
+
+func (s *Shape) shape() *Shape {
+	return s
+}
+
+// shape is implemented by the drawing instructions.
+type shape interface {
+	Instruction
+	shape() *Shape
+	//extent returns the right and bottom most coordinates of the shape
+	extent(p *Processor) (float64, float64)
+	//draw draws the shape at origin x, y with the fpdf draw style
+	draw(p *Processor, x, y float64, drawStyle string)
+}
+
+type Line struct {
+	Styled
+	Shape
+	XMLName xml.Name `xml:"line"`
+	X1      float64  `xml:"x1,attr"`
+	Y1      float64  `xml:"y1,attr"`
+	X2      float64  `xml:"x2,attr"`
+	Y2      float64  `xml:"y2,attr"`
+}
+
+func (l *Line) extent(p *Processor) (float64, float64) {
+	return math.Max(l.X1, l.X2), math.Max(l.Y1, l.Y2)
+}
+
+func (l *Line) draw(p *Processor, x, y float64, drawStyle string) {
+	p.pdf.Line(x+l.X1, y+l.Y1, x+l.X2, y+l.Y2)
+}
+
+// Rect is a rectangle. Without width it spans the current frame from X.
+type Rect struct {
+	Styled
+	Shape
+	XMLName xml.Name `xml:"rect"`
+	X       float64  `xml:"x,attr"`
+	Y       float64  `xml:"y,attr"`
+	Width   float64  `xml:"width,attr"`
+	Height  float64  `xml:"height,attr"`
+}
+
+func (r *Rect) width(p *Processor) float64 {
+	if r.Width > 0 {
+		return r.Width
+	}
+	return p.effectiveWidth(0) - r.X
+}
+
+func (r *Rect) extent(p *Processor) (float64, float64) {
+	return r.X + r.width(p), r.Y + r.Height
+}
+
+func (r *Rect) draw(p *Processor, x, y float64, drawStyle string) {
+	p.pdf.Rect(x+r.X, y+r.Y, r.width(p), r.Height, drawStyle)
+}
+
+// Ellipse is an ellipse around CX, CY. Without RY it is a circle.
+type Ellipse struct {
+	Styled
+	Shape
+	XMLName xml.Name `xml:"ellipse"`
+	CX      float64  `xml:"cx,attr"`
+	CY      float64  `xml:"cy,attr"`
+	RX      float64  `xml:"rx,attr"`
+	RY      float64  `xml:"ry,attr"`
+}
+
+func (e *Ellipse) ry() float64 {
+	if e.RY > 0 {
+		return e.RY
+	}
+	return e.RX
+}
+
+func (e *Ellipse) extent(p *Processor) (float64, float64) {
+	return e.CX + e.RX, e.CY + e.ry()
+}
+
+func (e *Ellipse) draw(p *Processor, x, y float64, drawStyle string) {
+	p.pdf.Ellipse(x+e.CX, y+e.CY, e.RX, e.ry(), 0, drawStyle)
+}
+
+// Polyline connects the points given as "x,y x,y ...". If Closed is set, it is a polygon.
+type Polyline struct {
+	Styled
+	Shape
+	XMLName xml.Name `xml:"polyline"`
+	Points  string   `xml:"points,attr"`
+	Closed  bool     `xml:"closed,attr"`
+	points  []float64
+}
+
+func (pl *Polyline) DecodeAttrs(attrs []xml.Attr) error {
+	err := pl.Styled.DecodeAttrs(attrs)
+	if err != nil {
+		return err
+	}
+	pl.points, err = parseShapeNumbers(pl.Points)
+	if err != nil {
+		return errors.Wrapf(err, "parse polyline points (%s)", pl.Points)
+	}
+	if len(pl.points) < 4 || len(pl.points)%2 != 0 {
+		return errors.Errorf("polyline points (%s) must be at least 2 pairs of x,y", pl.Points)
+	}
+	return nil
+}
+
+func (pl *Polyline) extent(p *Processor) (float64, float64) {
+	return pointsExtent(pl.points)
+}
+
+func (pl *Polyline) draw(p *Processor, x, y float64, drawStyle string) {
+	p.pdf.MoveTo(x+pl.points[0], y+pl.points[1])
+	for i := 2; i < len(pl.points); i += 2 {
+		p.pdf.LineTo(x+pl.points[i], y+pl.points[i+1])
+	}
+	if pl.Closed {
+		p.pdf.ClosePath()
+	} else {
+		drawStyle = "D"
+	}
+	p.pdf.DrawPath(drawStyle)
+}
+
+// Path is a path in the syntax of SVG path data, supporting the commands M, L, H, V, C, Q and Z, in upper
+// (absolute) and lower (relative) case.
+type Path struct {
+	Styled
+	Shape
+	XMLName  xml.Name `xml:"path"`
+	D        string   `xml:"d,attr"`
+	segments []pathSegment
+}
+
+// pathSegment is a path command with absolute coordinates. Op is one of M, L, C and Z.
+type pathSegment struct {
+	op     byte
+	coords []float64
+}
+
+func (pa *Path) DecodeAttrs(attrs []xml.Attr) error {
+	err := pa.Styled.DecodeAttrs(attrs)
+	if err != nil {
+		return err
+	}
+	pa.segments, err = parsePath(pa.D)
+	if err != nil {
+		return errors.Wrapf(err, "parse path (%s)", pa.D)
+	}
+	return nil
+}
+
+func (pa *Path) extent(p *Processor) (float64, float64) {
+	coords := []float64{}
+	for _, s := range pa.segments {
+		coords = append(coords, s.coords...)
+	}
+	return pointsExtent(coords)
+}
+
+func (pa *Path) draw(p *Processor, x, y float64, drawStyle string) {
+	for _, s := range pa.segments {
+		c := s.coords
+		switch s.op {
+		case 'M':
+			p.pdf.MoveTo(x+c[0], y+c[1])
+		case 'L':
+			p.pdf.LineTo(x+c[0], y+c[1])
+		case 'C':
+			p.pdf.CurveBezierCubicTo(x+c[0], y+c[1], x+c[2], y+c[3], x+c[4], y+c[5])
+		case 'Z':
+			p.pdf.ClosePath()
+		}
+	}
+	p.pdf.DrawPath(drawStyle)
+}
+
+func pointsExtent(coords []float64) (float64, float64) {
+	mx, my := 0.0, 0.0
+	for i := 0; i+1 < len(coords); i += 2 {
+		mx = math.Max(mx, coords[i])
+		my = math.Max(my, coords[i+1])
+	}
+	return mx, my
+}
+
+var shapeTokenRx = regexp.MustCompile(`[MmLlHhVvCcQqZz]|[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// shapeTokens splits s into commands and numbers, which may be separated by white space and commas.
+func shapeTokens(s string) ([]string, error) {
+	tokens := []string{}
+	pos := 0
+	for _, loc := range shapeTokenRx.FindAllStringIndex(s, -1) {
+		if strings.Trim(s[pos:loc[0]], " ,\t\r\n") != "" {
+			return nil, errors.Errorf("unexpected (%s)", s[pos:loc[0]])
+		}
+		tokens = append(tokens, s[loc[0]:loc[1]])
+		pos = loc[1]
+	}
+	if strings.Trim(s[pos:], " ,\t\r\n") != "" {
+		return nil, errors.Errorf("unexpected (%s)", s[pos:])
+	}
+	return tokens, nil
+}
+
+func parseShapeNumbers(s string) ([]float64, error) {
+	tokens, err := shapeTokens(s)
+	if err != nil {
+		return nil, err
+	}
+	ns := []float64{}
+	for _, t := range tokens {
+		n, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, errors.Errorf("expected number, got (%s)", t)
+		}
+		ns = append(ns, n)
+	}
+	return ns, nil
+}
+
+// parsePath parses SVG path data into segments with absolute coordinates.
+func parsePath(d string) ([]pathSegment, error) {
+	tokens, err := shapeTokens(d)
+	if err != nil {
+		return nil, err
+	}
+	argCounts := map[byte]int{'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'Q': 4, 'Z': 0}
+	segments := []pathSegment{}
+	var cmd byte
+	var cx, cy, sx, sy float64
+	for pos := 0; pos < len(tokens); {
+		if n, err := strconv.ParseFloat(tokens[pos], 64); err == nil {
+			if cmd == 0 || cmd == 'Z' || cmd == 'z' {
+				return nil, errors.Errorf("number (%v) without command", n)
+			}
+		} else {
+			cmd = tokens[pos][0]
+			pos++
+		}
+		op := strings.ToUpper(string(cmd))[0]
+		rel := cmd != op
+		count := argCounts[op]
+		if pos+count > len(tokens) {
+			return nil, errors.Errorf("command (%c) needs %d numbers", cmd, count)
+		}
+		args := make([]float64, count)
+		for i := range args {
+			args[i], err = strconv.ParseFloat(tokens[pos+i], 64)
+			if err != nil {
+				return nil, errors.Errorf("command (%c) needs %d numbers", cmd, count)
+			}
+		}
+		pos += count
+		if len(segments) == 0 && op != 'M' {
+			return nil, errors.Errorf("path must start with M")
+		}
+
+		switch op {
+		case 'H':
+			op, args = 'L', []float64{args[0], cy}
+			if rel {
+				args[1] = 0
+			}
+		case 'V':
+			op, args = 'L', []float64{cx, args[0]}
+			if rel {
+				args[0] = 0
+			}
+		}
+		if rel {
+			for i := range args {
+				if i%2 == 0 {
+					args[i] += cx
+				} else {
+					args[i] += cy
+				}
+			}
+		}
+		if op == 'Q' {
+			//quadratic curves are drawn as the equivalent cubic ones
+			qx, qy, ex, ey := args[0], args[1], args[2], args[3]
+			op, args = 'C', []float64{cx + 2*(qx-cx)/3, cy + 2*(qy-cy)/3, ex + 2*(qx-ex)/3, ey + 2*(qy-ey)/3, ex, ey}
+		}
+		switch op {
+		case 'Z':
+			cx, cy = sx, sy
+		default:
+			cx, cy = args[len(args)-2], args[len(args)-1]
+		}
+		if op == 'M' {
+			sx, sy = cx, cy
+			//further coordinate pairs of a move are lines
+			if rel {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		}
+		segments = append(segments, pathSegment{op: op, coords: args})
+	}
+	return segments, nil
+}
+
+func (p *Processor) renderShape(s shape, sty style.Styles) {
+	x, y := 0.0, 0.0
+	if s.shape().Position != ShapePositionAbsolute {
+		_, bottom := s.extent(p)
+		p.ensureSpace(bottom)
+		x, y = p.pdf.GetXY()
+	}
+	p.pdf.SetLineWidth(sty.Draw.LineWidth)
+	p.pdf.SetDrawColor(int(sty.Color.Foreground.R), int(sty.Color.Foreground.G), int(sty.Color.Foreground.B))
+	p.pdf.SetFillColor(int(sty.Color.Background.R), int(sty.Color.Background.G), int(sty.Color.Background.B))
+	drawStyle := "D"
+	if s.shape().Fill {
+		drawStyle = "FD"
+	}
+	s.draw(p, x, y, drawStyle)
+	if s.shape().Position != ShapePositionAbsolute {
+		_, bottom := s.extent(p)
+		p.pdf.SetY(y + bottom)
+	}
+}
+
+// shapeHeight returns the vertical space a shape takes in the flow.
+func (p *Processor) shapeHeight(s shape) float64 {
+	if s.shape().Position == ShapePositionAbsolute {
+		return 0
+	}
+	_, bottom := s.extent(p)
+	return bottom
+}
+
+// shapeWidth returns the horizontal space a shape takes in the flow.
+func (p *Processor) shapeWidth(s shape) float64 {
+	if s.shape().Position == ShapePositionAbsolute {
+		return 0
+	}
+	right, _ := s.extent(p)
+	return right
+}
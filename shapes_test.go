@@ -0,0 +1,101 @@
+package gompdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestShapeTokens(t *testing.T) {
+	tests := []struct {
+		in     string
+		tokens []string
+		err    string
+	}{
+		{in: "", tokens: []string{}},
+		{in: "M1,2 L 3 4z", tokens: []string{"M", "1", "2", "L", "3", "4", "z"}},
+		{in: "1.5.5", tokens: []string{"1.5", ".5"}},
+		{in: "-1-2", tokens: []string{"-1", "-2"}},
+		{in: "+1e2-3E-1", tokens: []string{"+1e2", "-3E-1"}},
+		{in: " \t1 ,\n2 ", tokens: []string{"1", "2"}},
+		{in: "M 1 2 X 3", err: "unexpected ( X )"},
+		{in: "1 2 #", err: "unexpected ( #)"},
+	}
+	for _, test := range tests {
+		tokens, err := shapeTokens(test.in)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: want error %q, got %v", test.in, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(tokens, test.tokens) {
+			t.Errorf("%q: want tokens %q, got %q", test.in, test.tokens, tokens)
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	seg := func(op byte, coords ...float64) pathSegment {
+		return pathSegment{op: op, coords: append([]float64{}, coords...)}
+	}
+	tests := []struct {
+		d        string
+		segments []pathSegment
+		err      string
+	}{
+		{d: "M 1 2 L 3 4 Z", segments: []pathSegment{seg('M', 1, 2), seg('L', 3, 4), seg('Z')}},
+		{d: "m 1 2 l 3 4 z", segments: []pathSegment{seg('M', 1, 2), seg('L', 4, 6), seg('Z')}},
+		//relative coordinates continue from the absolute ones and vice versa
+		{d: "M 10 10 l 5 5 L 0 0 l 1 2", segments: []pathSegment{seg('M', 10, 10), seg('L', 15, 15), seg('L', 0, 0), seg('L', 1, 2)}},
+		//after closing, the current point is the start of the subpath
+		{d: "M 10 10 L 20 20 Z l 1 1", segments: []pathSegment{seg('M', 10, 10), seg('L', 20, 20), seg('Z'), seg('L', 11, 11)}},
+		{d: "M 1 2 H 5 V 7", segments: []pathSegment{seg('M', 1, 2), seg('L', 5, 2), seg('L', 5, 7)}},
+		{d: "M 1 2 h 5 v 7", segments: []pathSegment{seg('M', 1, 2), seg('L', 6, 2), seg('L', 6, 9)}},
+		{d: "M 1 2 H 3 4", segments: []pathSegment{seg('M', 1, 2), seg('L', 3, 2), seg('L', 4, 2)}},
+		{d: "M 0 0 C 1 2 3 4 5 6 c 1 1 2 2 3 3", segments: []pathSegment{seg('M', 0, 0), seg('C', 1, 2, 3, 4, 5, 6), seg('C', 6, 7, 7, 8, 8, 9)}},
+		//quadratic curves become cubic ones with the control points at 2/3 towards the quadratic control point
+		{d: "M 0 0 Q 3 3 6 0", segments: []pathSegment{seg('M', 0, 0), seg('C', 2, 2, 4, 2, 6, 0)}},
+		{d: "M 6 0 q 3 3 6 0", segments: []pathSegment{seg('M', 6, 0), seg('C', 8, 2, 10, 2, 12, 0)}},
+		//further coordinate pairs of a move are lines
+		{d: "M 1 1 2 2 3 3", segments: []pathSegment{seg('M', 1, 1), seg('L', 2, 2), seg('L', 3, 3)}},
+		{d: "m 1 1 2 2 3 3", segments: []pathSegment{seg('M', 1, 1), seg('L', 3, 3), seg('L', 6, 6)}},
+		{d: "M1-2l.5.5", segments: []pathSegment{seg('M', 1, -2), seg('L', 1.5, -1.5)}},
+		{d: "", segments: []pathSegment{}},
+
+		{d: "1 2", err: "number (1) without command"},
+		{d: "M 1 2 Z 3 4", err: "number (3) without command"},
+		{d: "L 1 2", err: "path must start with M"},
+		{d: "M 1", err: "command (M) needs 2 numbers"},
+		{d: "M 1 2 C 1 2 3 4 5", err: "command (C) needs 6 numbers"},
+		{d: "M 1 2 L 3 Z", err: "command (L) needs 2 numbers"},
+		{d: "M 1 2 A 1 1 0 0 1 3 3", err: "unexpected ( A )"},
+	}
+	for _, test := range tests {
+		segments, err := parsePath(test.d)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%q: want error %q, got %v", test.d, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.d, err)
+			continue
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("%q: want segments %v, got %v", test.d, test.segments, segments)
+		}
+	}
+}
+
+func TestPathDecodeError(t *testing.T) {
+	_, err := Load(strings.NewReader(`<document><body><path d="M 1 2 L 3"/></body></document>`))
+	if err == nil || !strings.Contains(err.Error(), "parse path (M 1 2 L 3)") {
+		t.Errorf("want parse path error, got %v", err)
+	}
+}
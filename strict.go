@@ -0,0 +1,135 @@
+package gompdf
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WithStrict makes Process fail on problems, which are otherwise worked around: documents failing validation
+// (see Validate), malformed colors, undecodable cell instructions, row-spans exceeding their table and links to
+// undefined anchors.
+func WithStrict() ProcessOption {
+	return func(p *Processor) error {
+		p.strict = true
+		return nil
+	}
+}
+
+// LoadStrict loads the document like Load, but fails on documents failing validation (see Validate), e.g. with
+// malformed colors, and on undecodable cell instructions.
+func LoadStrict(r io.Reader) (*Document, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read-all")
+	}
+	doc, err := loadBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	err = checkStrict(doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// checkStrict returns an error, if the document fails validation or contains undecodable cell instructions.
+func checkStrict(doc *Document) error {
+	err := validationError(Validate(doc))
+	if err != nil {
+		return err
+	}
+	for _, is := range []Instructions{doc.Header, doc.Footer, doc.Body} {
+		err = decodeError(is.iss)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validationError(diags []Diagnostic) error {
+	if len(diags) == 0 {
+		return nil
+	}
+	msgs := []string{}
+	for _, d := range diags {
+		msgs = append(msgs, d.String())
+	}
+	return errors.Errorf("invalid document: %s", strings.Join(msgs, "; "))
+}
+
+// decodeError returns the first decode error recorded in the cells of tables in is.
+func decodeError(is []Instruction) error {
+	for _, i := range is {
+		if c, ok := i.(*Columns); ok {
+			err := decodeError(c.Instructions.iss)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		t, ok := i.(*Table)
+		if !ok {
+			continue
+		}
+		for _, row := range t.Rows {
+			for _, c := range row.Cells {
+				if c.decodeErr != nil {
+					return errors.Wrap(c.decodeErr, "decode cell instruction")
+				}
+				err := decodeError(c.Instructions)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// styleProblems returns the problems of the document's style classes and style attributes. Properties with
+// malformed colors aren't applied.
+func (doc *Document) styleProblems() []error {
+	errs := doc.styleClasses.Problems()
+	var walk func(is []Instruction)
+	walk = func(is []Instruction) {
+		for _, i := range is {
+			if s, ok := i.(interface{ styleProblems() []error }); ok {
+				for _, err := range s.styleProblems() {
+					errs = append(errs, errors.Wrapf(err, "style of <%s>", xmlName(i)))
+				}
+			}
+			switch i := i.(type) {
+			case *Columns:
+				walk(i.Instructions.iss)
+			case *Table:
+				for _, row := range i.Rows {
+					walk([]Instruction{row})
+				}
+			case *TableRow:
+				for _, c := range i.Cells {
+					walk([]Instruction{c})
+				}
+			case *TableCell:
+				walk(i.Instructions)
+			}
+		}
+	}
+	for _, is := range []Instructions{doc.Header, doc.Footer, doc.Body} {
+		walk(is.iss)
+	}
+	return errs
+}
+
+// problem reports a problem, which is worked around. In strict mode it halts processing with err.
+func (p *Processor) problem(err error) {
+	if p.strict {
+		p.pdf.SetError(err)
+		return
+	}
+	Logf("%v", err)
+}
@@ -0,0 +1,57 @@
+package gompdf
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestMalformedColors(t *testing.T) {
+	src := `<document>
+<style>warn { color: #ff00 }</style>
+<body>
+<text class="warn">a</text>
+<table><tr><td><box style="background-color: red">b</box></td></tr></table>
+</body>
+</document>`
+	doc, err := Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("want malformed colors not to fail loading, got %v", err)
+	}
+	if n := len(doc.styleProblems()); n != 2 {
+		t.Errorf("want 2 style problems, got %v", doc.styleProblems())
+	}
+	processTestSource(t, src)
+
+	_, err = LoadStrict(strings.NewReader(src))
+	if err == nil {
+		t.Errorf("want strict loading to fail")
+	}
+	p, err := NewProcessor(doc, WithStrict())
+	if err != nil {
+		t.Fatalf("new processor: %v", err)
+	}
+	err = p.Process(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "invalid color hex-string") {
+		t.Errorf("want strict processing to fail on the color, got %v", err)
+	}
+	//without source the decoded styles are checked
+	doc.source = nil
+	if err := checkStrict(doc); err == nil {
+		t.Errorf("want strict check without source to fail")
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	cr, err := ParseHexColor("#102030")
+	if err != nil || cr != (color.RGBA{R: 16, G: 32, B: 48}) {
+		t.Errorf("want rgb(16, 32, 48), got %v (%v)", cr, err)
+	}
+	if _, err := ParseHexColor("#10203"); err == nil {
+		t.Errorf("want error for malformed color")
+	}
+	if cr := RGBAFromHexColor("nope"); cr != (color.RGBA{}) {
+		t.Errorf("want black for malformed color, got %v", cr)
+	}
+}
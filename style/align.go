@@ -6,6 +6,8 @@ const (
 	HAlignLeft   HAlign = "left"
 	HAlignRight  HAlign = "right"
 	HAlignCenter HAlign = "center"
+	//HAlignJustify stretches the word gaps to fill the line, except on the last line of a paragraph
+	HAlignJustify HAlign = "justify"
 )
 
 type VAlign string
@@ -3,6 +3,8 @@ package style
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -14,6 +16,66 @@ type Border struct {
 	Bottom int
 }
 
+type BorderLineStyle string
+
+const (
+	BorderLineSolid  BorderLineStyle = "solid"
+	BorderLineDashed BorderLineStyle = "dashed"
+	BorderLineDotted BorderLineStyle = "dotted"
+)
+
+// BorderWidth holds the line widths of the border sides. If not set, line-width is used.
+type BorderWidth struct {
+	Set    bool
+	Left   float64
+	Top    float64
+	Right  float64
+	Bottom float64
+}
+
+// BorderColor holds the colors of the border sides. If not set, color is used.
+type BorderColor struct {
+	Set    bool
+	Left   RGB
+	Top    RGB
+	Right  RGB
+	Bottom RGB
+}
+
+// BorderStyle holds the line styles of the border sides. Empty styles are solid.
+type BorderStyle struct {
+	Left   BorderLineStyle
+	Top    BorderLineStyle
+	Right  BorderLineStyle
+	Bottom BorderLineStyle
+}
+
+// BorderRadius holds the corner radii of a box.
+type BorderRadius struct {
+	TopLeft     float64
+	TopRight    float64
+	BottomRight float64
+	BottomLeft  float64
+}
+
+// BoxShadow is a shadow of the box's shape offset by OffsetX, OffsetY.
+type BoxShadow struct {
+	Set     bool
+	OffsetX float64
+	OffsetY float64
+	Color   RGB
+}
+
+type Float string
+
+const (
+	FloatNone Float = "none"
+	//FloatLeft places a block at the left of the frame, the following text wraps around it on the right
+	FloatLeft Float = "left"
+	//FloatRight places a block at the right of the frame, the following text wraps around it on the left
+	FloatRight Float = "right"
+)
+
 type Padding struct {
 	Left   float64
 	Top    float64
@@ -29,9 +91,18 @@ type Margin struct {
 }
 
 type Box struct {
-	Border  Border  `style:"border"`
-	Padding Padding `style:"padding"`
-	Margin  Margin  `style:"margin"`
+	Border      Border      `style:"border"`
+	BorderWidth BorderWidth `style:"border-width"`
+	BorderColor BorderColor `style:"border-color"`
+	BorderStyle BorderStyle `style:"border-style"`
+	//BorderRadius is given as (all) or (top-left,top-right,bottom-right,bottom-left)
+	BorderRadius BorderRadius `style:"border-radius"`
+	//BoxShadow is given as (offset-x,offset-y) or (offset-x,offset-y,color) or none
+	BoxShadow BoxShadow `style:"box-shadow"`
+	Padding   Padding   `style:"padding"`
+	Margin    Margin    `style:"margin"`
+	//Float applies to images. The margin separates floating images from the text
+	Float Float `style:"float"`
 }
 
 func (b *Border) UnmarshalStyle(v string) error {
@@ -42,6 +113,118 @@ func (b *Border) UnmarshalStyle(v string) error {
 	return nil
 }
 
+// sideValues splits a value of the form (all) or (left,top,right,bottom) into the values of the four sides
+// (or corners).
+func sideValues(v string) ([]string, error) {
+	vs := strings.Split(v, ",")
+	for i := range vs {
+		vs[i] = trimWS(vs[i])
+	}
+	switch len(vs) {
+	case 1:
+		return []string{vs[0], vs[0], vs[0], vs[0]}, nil
+	case 4:
+		return vs, nil
+	default:
+		return nil, errors.Errorf("expected 1 or 4 values, got %d", len(vs))
+	}
+}
+
+func (b *BorderWidth) UnmarshalStyle(v string) error {
+	vs, err := sideValues(v)
+	if err != nil {
+		return errors.Wrapf(err, "scan border-width value (%s)", v)
+	}
+	ws := make([]float64, 4)
+	for i, s := range vs {
+		ws[i], err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.Wrapf(err, "scan border-width value (%s)", v)
+		}
+	}
+	*b = BorderWidth{Set: true, Left: ws[0], Top: ws[1], Right: ws[2], Bottom: ws[3]}
+	return nil
+}
+
+func (b *BorderColor) UnmarshalStyle(v string) error {
+	vs, err := sideValues(v)
+	if err != nil {
+		return errors.Wrapf(err, "scan border-color value (%s)", v)
+	}
+	cs := make([]RGB, 4)
+	for i, s := range vs {
+		err = cs[i].UnmarshalStyle(s)
+		if err != nil {
+			return errors.Wrapf(err, "scan border-color value (%s)", v)
+		}
+	}
+	*b = BorderColor{Set: true, Left: cs[0], Top: cs[1], Right: cs[2], Bottom: cs[3]}
+	return nil
+}
+
+func (b *BorderStyle) UnmarshalStyle(v string) error {
+	vs, err := sideValues(v)
+	if err != nil {
+		return errors.Wrapf(err, "scan border-style value (%s)", v)
+	}
+	ls := make([]BorderLineStyle, 4)
+	for i, s := range vs {
+		switch l := BorderLineStyle(s); l {
+		case BorderLineSolid, BorderLineDashed, BorderLineDotted:
+			ls[i] = l
+		default:
+			return errors.Errorf("invalid border-style (%s), must be one of solid, dashed and dotted", s)
+		}
+	}
+	*b = BorderStyle{Left: ls[0], Top: ls[1], Right: ls[2], Bottom: ls[3]}
+	return nil
+}
+
+func (b *BorderRadius) UnmarshalStyle(v string) error {
+	vs, err := sideValues(v)
+	if err != nil {
+		return errors.Wrapf(err, "scan border-radius value (%s)", v)
+	}
+	rs := make([]float64, 4)
+	for i, s := range vs {
+		rs[i], err = strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.Wrapf(err, "scan border-radius value (%s)", v)
+		}
+	}
+	*b = BorderRadius{TopLeft: rs[0], TopRight: rs[1], BottomRight: rs[2], BottomLeft: rs[3]}
+	return nil
+}
+
+func (b *BoxShadow) UnmarshalStyle(v string) error {
+	if trimWS(v) == "none" {
+		*b = BoxShadow{}
+		return nil
+	}
+	vs := strings.Split(v, ",")
+	if len(vs) != 2 && len(vs) != 3 {
+		return errors.Errorf("scan box-shadow value (%s): expected offset-x,offset-y[,color]", v)
+	}
+	shadow := BoxShadow{Set: true, Color: makeRGB(128, 128, 128)}
+	var err error
+	shadow.OffsetX, err = strconv.ParseFloat(trimWS(vs[0]), 64)
+	if err != nil {
+		return errors.Wrapf(err, "scan box-shadow value (%s)", v)
+	}
+	shadow.OffsetY, err = strconv.ParseFloat(trimWS(vs[1]), 64)
+	if err != nil {
+		return errors.Wrapf(err, "scan box-shadow value (%s)", v)
+	}
+	if len(vs) == 3 {
+		err = shadow.Color.UnmarshalStyle(trimWS(vs[2]))
+		if err != nil {
+			return errors.Wrapf(err, "scan box-shadow value (%s)", v)
+		}
+	}
+	*b = shadow
+	return nil
+}
+
 func (b *Padding) UnmarshalStyle(v string) error {
 	_, err := fmt.Fscanf(bytes.NewBufferString(v), "%f,%f,%f,%f", &b.Left, &b.Top, &b.Right, &b.Bottom)
 	if err != nil {
@@ -0,0 +1,115 @@
+package style
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// styleUnmarshaler is implemented by the style values parsed from their property values.
+type styleUnmarshaler interface {
+	UnmarshalStyle(v string) error
+}
+
+type unmarshalTest struct {
+	v    string
+	want interface{}
+	err  string
+}
+
+// testUnmarshalStyle unmarshals the values of tests into values created by newValue.
+func testUnmarshalStyle(t *testing.T, newValue func() styleUnmarshaler, tests []unmarshalTest) {
+	t.Helper()
+	for _, test := range tests {
+		value := newValue()
+		err := value.UnmarshalStyle(test.v)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%T (%s): want error %q, got %v", value, test.v, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%T (%s): %v", value, test.v, err)
+			continue
+		}
+		if got := reflect.ValueOf(value).Elem().Interface(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%T (%s): want %v, got %v", value, test.v, test.want, got)
+		}
+	}
+}
+
+func TestBorderUnmarshalStyle(t *testing.T) {
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &Border{} }, []unmarshalTest{
+		{v: "1,0,1,0", want: Border{Left: 1, Top: 0, Right: 1, Bottom: 0}},
+		{v: "1", err: "scan border value (1)"},
+		{v: "1,a,1,1", err: "scan border value (1,a,1,1)"},
+	})
+}
+
+func TestBorderWidthUnmarshalStyle(t *testing.T) {
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &BorderWidth{} }, []unmarshalTest{
+		{v: "0.5", want: BorderWidth{Set: true, Left: 0.5, Top: 0.5, Right: 0.5, Bottom: 0.5}},
+		{v: " 1, 2 ,3,4 ", want: BorderWidth{Set: true, Left: 1, Top: 2, Right: 3, Bottom: 4}},
+		{v: "1,2", err: "expected 1 or 4 values, got 2"},
+		{v: "1,2,x,4", err: "scan border-width value (1,2,x,4)"},
+		{v: "", err: "scan border-width value ()"},
+	})
+}
+
+func TestBorderColorUnmarshalStyle(t *testing.T) {
+	red, blue := RGB{255, 0, 0}, RGB{0, 0, 255}
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &BorderColor{} }, []unmarshalTest{
+		{v: "#ff0000", want: BorderColor{Set: true, Left: red, Top: red, Right: red, Bottom: red}},
+		{v: "#ff0000,#0000ff,#ff0000,#0000ff", want: BorderColor{Set: true, Left: red, Top: blue, Right: red, Bottom: blue}},
+		{v: "#ff0000,#0000ff,#ff0000", err: "expected 1 or 4 values, got 3"},
+		{v: "#ff0000,#0000ff,#ff00,#0000ff", err: "invalid color hex-string (#ff00)"},
+	})
+	//malformed colors are reported as color errors, so that they are problems instead of failing the decoding
+	var c BorderColor
+	err := c.UnmarshalStyle("#ff00")
+	if _, ok := errors.Cause(err).(*ColorError); !ok {
+		t.Errorf("want a color error, got %v", err)
+	}
+}
+
+func TestBorderStyleUnmarshalStyle(t *testing.T) {
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &BorderStyle{} }, []unmarshalTest{
+		{v: "dashed", want: BorderStyle{Left: BorderLineDashed, Top: BorderLineDashed, Right: BorderLineDashed, Bottom: BorderLineDashed}},
+		{v: "solid, dotted, solid, dashed", want: BorderStyle{Left: BorderLineSolid, Top: BorderLineDotted, Right: BorderLineSolid, Bottom: BorderLineDashed}},
+		{v: "solid,dotted", err: "expected 1 or 4 values, got 2"},
+		{v: "double", err: "invalid border-style (double), must be one of solid, dashed and dotted"},
+	})
+}
+
+func TestBorderRadiusUnmarshalStyle(t *testing.T) {
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &BorderRadius{} }, []unmarshalTest{
+		{v: "2", want: BorderRadius{TopLeft: 2, TopRight: 2, BottomRight: 2, BottomLeft: 2}},
+		{v: "1,2,3,4", want: BorderRadius{TopLeft: 1, TopRight: 2, BottomRight: 3, BottomLeft: 4}},
+		{v: "1,2,3", err: "expected 1 or 4 values, got 3"},
+		{v: "1,2,3,r", err: "scan border-radius value (1,2,3,r)"},
+	})
+}
+
+func TestBoxShadowUnmarshalStyle(t *testing.T) {
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &BoxShadow{Set: true, OffsetX: 9} }, []unmarshalTest{
+		{v: "1,2", want: BoxShadow{Set: true, OffsetX: 1, OffsetY: 2, Color: RGB{128, 128, 128}}},
+		{v: " -1.5 , 2 , #102030 ", want: BoxShadow{Set: true, OffsetX: -1.5, OffsetY: 2, Color: RGB{16, 32, 48}}},
+		{v: " none ", want: BoxShadow{}},
+		{v: "1", err: "scan box-shadow value (1): expected offset-x,offset-y[,color]"},
+		{v: "1,2,#102030,4", err: "scan box-shadow value (1,2,#102030,4): expected offset-x,offset-y[,color]"},
+		{v: "x,2", err: "scan box-shadow value (x,2)"},
+		{v: "1,y", err: "scan box-shadow value (1,y)"},
+		{v: "1,2,#1020", err: "invalid color hex-string (#1020)"},
+	})
+}
+
+func TestMarginUnmarshalStyle(t *testing.T) {
+	testUnmarshalStyle(t, func() styleUnmarshaler { return &Margin{} }, []unmarshalTest{
+		{v: "1,2.5,3,4", want: Margin{Left: 1, Top: 2.5, Right: 3, Bottom: 4}},
+		{v: "-1,0,0,-2", want: Margin{Left: -1, Bottom: -2}},
+		{v: "1,2,3", err: "scan margin value (1,2,3)"},
+	})
+}
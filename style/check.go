@@ -0,0 +1,83 @@
+package style
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Problem is an error in a style or style sheet at byte Offset.
+type Problem struct {
+	Offset  int
+	Message string
+}
+
+// Check reports syntax errors, unknown properties and invalid values (e.g. colors) of the style s.
+func Check(s string) []Problem {
+	return checkDeclarations(s, 0)
+}
+
+func checkDeclarations(s string, offset int) []Problem {
+	ps := []Problem{}
+	protoType := reflect.TypeOf(Styles{})
+	pos := offset
+	for _, decl := range strings.Split(s, ";") {
+		declOffset := pos + len(decl) - len(strings.TrimLeft(decl, " \r\n\t"))
+		pos += len(decl) + 1
+		decl = trimWS(decl)
+		if len(decl) == 0 {
+			continue
+		}
+		kv := strings.Split(decl, ":")
+		if len(kv) != 2 {
+			ps = append(ps, Problem{Offset: declOffset, Message: fmt.Sprintf("invalid style syntax (%s) must be of (key:val)", decl)})
+			continue
+		}
+		k, v := trimWS(kv[0]), trimWS(kv[1])
+		_, found, err := makeApplyFnc(protoType, k, v, []int{})
+		if err != nil {
+			ps = append(ps, Problem{Offset: declOffset, Message: fmt.Sprintf("invalid value (%s) of style property (%s): %v", v, k, err)})
+		} else if !found {
+			ps = append(ps, Problem{Offset: declOffset, Message: fmt.Sprintf("unknown style property (%s)", k)})
+		}
+	}
+	return ps
+}
+
+// CheckClasses reports the problems of the style sheet s like Check does for each class. It returns the names of
+// the declared classes.
+func CheckClasses(s string) ([]string, []Problem) {
+	names := []string{}
+	declared := map[string]bool{}
+	ps := []Problem{}
+	pos := 0
+	for {
+		curr := s[pos:]
+		i := strings.IndexByte(curr, '{')
+		if i < 0 {
+			return names, ps
+		}
+		nameOffset := pos + len(curr[:i]) - len(strings.TrimLeft(curr[:i], " \r\n\t"))
+		name := trimWS(curr[:i])
+		in := strings.IndexByte(curr[i:], '}')
+		if in < 0 {
+			ps = append(ps, Problem{Offset: pos + i, Message: "non matching brace"})
+			return names, ps
+		}
+		in += i
+		switch {
+		case len(name) == 0:
+			ps = append(ps, Problem{Offset: pos + i, Message: "style class without name"})
+		case strings.Index(name, ":") > 0:
+			className := name[:strings.Index(name, ":")]
+			if !declared[className] {
+				ps = append(ps, Problem{Offset: nameOffset, Message: fmt.Sprintf("no base class for (%s)", name)})
+			}
+		default:
+			declared[name] = true
+			names = append(names, name)
+		}
+		ps = append(ps, checkDeclarations(curr[i+1:in], pos+i+1)...)
+		pos += in + 1
+	}
+}
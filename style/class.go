@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -51,6 +52,33 @@ func (cs Classes) ApplyWithSelector(sel string, styles *Styles, classes ...strin
 	}
 }
 
+// Problems returns the problems of the classes and their selectors (see Applier.Problems), ordered by class name.
+func (cs Classes) Problems() []error {
+	names := []string{}
+	for name := range cs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := []error{}
+	for _, name := range names {
+		c := cs[name]
+		for _, err := range c.applier.Problems() {
+			errs = append(errs, errors.Wrapf(err, "style class (%s)", name))
+		}
+		sels := []string{}
+		for sel := range c.Selectors {
+			sels = append(sels, sel)
+		}
+		sort.Strings(sels)
+		for _, sel := range sels {
+			for _, err := range c.Selectors[sel].applier.Problems() {
+				errs = append(errs, errors.Wrapf(err, "style class (%s:%s)", name, sel))
+			}
+		}
+	}
+	return errs
+}
+
 func DecodeClasses(r io.Reader) (Classes, error) {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-
-	"github.com/pkg/errors"
 )
 
 type RGB struct {
@@ -26,21 +24,36 @@ func makeRGB(r, g, b uint8) RGB {
 
 var Black RGB = RGB{0, 0, 0}
 var White RGB = RGB{255, 255, 255}
+var Blue RGB = RGB{0, 0, 238}
 
-func (c *RGB) UnmarshalStyle(s string) error {
-	if strings.HasPrefix(s, "#") {
-		s = s[1:]
+// ColorError is the error of a malformed color. Styles don't fail decoding on it, see Applier.Problems.
+type ColorError struct {
+	Value string
+}
+
+func (e *ColorError) Error() string {
+	return fmt.Sprintf("invalid color hex-string (%s)", e.Value)
+}
+
+// ParseHexColor parses a color of the form #rrggbb, where the # is optional.
+func ParseHexColor(s string) (RGB, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 {
+		return RGB{}, &ColorError{Value: s}
 	}
-	if len(s) != 6 {
-		return errors.Errorf("invalid color hex-string (%s)", s)
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGB{}, &ColorError{Value: s}
 	}
-	n, err := strconv.ParseUint(s, 16, 32)
+	return makeRGB(uint8(n>>16), uint8(n>>8), uint8(n)), nil
+}
+
+func (c *RGB) UnmarshalStyle(s string) error {
+	rgb, err := ParseHexColor(s)
 	if err != nil {
-		return errors.Wrapf(err, "parse color hex string (%s)", s)
+		return err
 	}
-	c.R = uint8(n >> 16)
-	c.G = uint8(n >> 8)
-	c.B = uint8(n)
+	*c = rgb
 	return nil
 }
 
@@ -48,4 +61,5 @@ type Color struct {
 	Foreground RGB `style:"color"`
 	Text       RGB `style:"text-color"`
 	Background RGB `style:"background-color"`
+	Link       RGB `style:"link-color"`
 }
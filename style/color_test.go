@@ -0,0 +1,53 @@
+package style
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		s    string
+		rgb  RGB
+		fail bool
+	}{
+		{s: "#102030", rgb: RGB{16, 32, 48}},
+		{s: "ffFFff", rgb: White},
+		{s: "#12345", fail: true},
+		{s: "#1234567", fail: true},
+		{s: "#12345g", fail: true},
+		{s: "+12345", fail: true},
+		{s: "", fail: true},
+	}
+	for _, test := range tests {
+		rgb, err := ParseHexColor(test.s)
+		if test.fail {
+			if _, ok := err.(*ColorError); !ok {
+				t.Errorf("ParseHexColor(%q): want color error, got %v", test.s, err)
+			}
+			continue
+		}
+		if err != nil || rgb != test.rgb {
+			t.Errorf("ParseHexColor(%q): want %v, got %v (%v)", test.s, test.rgb, rgb, err)
+		}
+	}
+}
+
+func TestApplierColorProblems(t *testing.T) {
+	a, err := DecodeApplier(bytes.NewBufferString("color: #zzzzzz; background-color: #102030; border-color: #000000 #12"))
+	if err != nil {
+		t.Fatalf("want malformed colors not to fail decoding, got %v", err)
+	}
+	if n := len(a.Problems()); n != 2 {
+		t.Errorf("want 2 problems, got %v", a.Problems())
+	}
+	sty := Styles{Color: Color{Foreground: White}}
+	a.Apply(&sty)
+	if sty.Color.Foreground != White || sty.Color.Background != (RGB{16, 32, 48}) || sty.Box.BorderColor.Set {
+		t.Errorf("want only the well-formed color applied, got %v", sty.Color)
+	}
+	_, err = DecodeApplier(bytes.NewBufferString("font-point-size: ten"))
+	if err == nil {
+		t.Errorf("want other malformed values to fail decoding")
+	}
+}
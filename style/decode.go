@@ -41,12 +41,21 @@ func ApplyNone(styles *Styles) {}
 
 type Applier struct {
 	fncs []ApplyFnc
+	//problems are the errors of properties, which aren't applied
+	problems []error
+}
+
+// Problems returns the errors of the properties with malformed colors. They don't fail decoding, but aren't
+// applied.
+func (a *Applier) Problems() []error {
+	return a.problems
 }
 
 func (a *Applier) Append(other *Applier) {
 	for _, f := range other.fncs {
 		a.fncs = append(a.fncs, f)
 	}
+	a.problems = append(a.problems, other.problems...)
 }
 
 func DecodeApplier(r io.Reader) (*Applier, error) {
@@ -64,6 +73,10 @@ func DecodeApplier(r io.Reader) (*Applier, error) {
 	protoType := reflect.TypeOf(Styles{})
 	for k, v := range raw {
 		fnc, found, err := makeApplyFnc(protoType, k, v, []int{})
+		if _, ok := errors.Cause(err).(*ColorError); ok {
+			a.problems = append(a.problems, errors.Wrapf(err, "style property (%s: %s)", k, v))
+			continue
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "make-apply-fnc (%s, %s)", k, v)
 		}
@@ -131,6 +144,14 @@ func makeSetValueFnc(kind reflect.Kind, styleValue string) (func(v reflect.Value
 		return func(v reflect.Value) {
 			v.SetString(styleValue)
 		}, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(styleValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse-bool (%s)", styleValue)
+		}
+		return func(v reflect.Value) {
+			v.SetBool(b)
+		}, nil
 	case reflect.Float32, reflect.Float64:
 		f, err := strconv.ParseFloat(styleValue, 64)
 		if err != nil {
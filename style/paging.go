@@ -0,0 +1,19 @@
+package style
+
+type PageBreakInside string
+
+const (
+	PageBreakInsideAuto PageBreakInside = "auto"
+	//PageBreakInsideAvoid moves a block to the next page, if it doesn't fit on the current one, but on an empty page
+	PageBreakInsideAvoid PageBreakInside = "avoid"
+)
+
+type Paging struct {
+	PageBreakInside PageBreakInside `style:"page-break-inside"`
+	//KeepWithNext keeps a block on the page, where the next block starts
+	KeepWithNext bool `style:"keep-with-next"`
+	//Widows is the minimal number of lines of a paragraph at the top of a page
+	Widows int `style:"widows"`
+	//Orphans is the minimal number of lines of a paragraph at the bottom of a page
+	Orphans int `style:"orphans"`
+}
@@ -8,4 +8,6 @@ type Styles struct {
 	Align
 	Color
 	Draw
+	Wrap
+	Paging
 }
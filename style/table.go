@@ -1,7 +1,19 @@
 package style
 
+type TableLayout string
+
+const (
+	//TableLayoutFixed uses explicit column widths and splits the remaining width evenly
+	TableLayoutFixed TableLayout = "fixed"
+	//TableLayoutAuto sizes columns without explicit width by their content
+	TableLayoutAuto TableLayout = "auto"
+)
+
 type Table struct {
-	ColumnWidth float64 `style:"column-width"`
-	ColumnSpan  int     `style:"column-span"`
-	RowSpan     int     `style:"row-span"`
+	Layout      TableLayout `style:"table-layout"`
+	ColumnWidth float64     `style:"column-width"`
+	ColumnSpan  int         `style:"column-span"`
+	RowSpan     int         `style:"row-span"`
+	//RepeatHeader marks leading rows, which are repeated on each page the table continues on
+	RepeatHeader bool `style:"repeat-header"`
 }
@@ -0,0 +1,43 @@
+package style
+
+type Hyphenate string
+
+const (
+	HyphenateNone Hyphenate = "none"
+	HyphenateEN   Hyphenate = "en"
+	HyphenateDE   Hyphenate = "de"
+)
+
+type WordBreak string
+
+const (
+	WordBreakNormal WordBreak = "normal"
+	//WordBreakBreakAll breaks words at any character to fill the line
+	WordBreakBreakAll WordBreak = "break-all"
+)
+
+type OverflowWrap string
+
+const (
+	//OverflowWrapNormal lets words wider than the line overflow
+	OverflowWrapNormal OverflowWrap = "normal"
+	//OverflowWrapAnywhere breaks words wider than the line at any character
+	OverflowWrapAnywhere OverflowWrap = "anywhere"
+)
+
+type Overflow string
+
+const (
+	OverflowVisible Overflow = "visible"
+	//OverflowHidden omits the lines, which don't fit into a box or cell with fixed height
+	OverflowHidden Overflow = "hidden"
+	//OverflowEllipsis omits the lines, which don't fit, and ends the last visible line with an ellipsis
+	OverflowEllipsis Overflow = "ellipsis"
+)
+
+type Wrap struct {
+	Hyphenate    Hyphenate    `style:"hyphenate"`
+	WordBreak    WordBreak    `style:"word-break"`
+	OverflowWrap OverflowWrap `style:"overflow-wrap"`
+	Overflow     Overflow     `style:"overflow"`
+}
@@ -2,6 +2,7 @@ package gompdf
 
 import (
 	"encoding/xml"
+	"strings"
 
 	"github.com/mazzegi/gompdf/style"
 	"github.com/pkg/errors"
@@ -56,6 +57,14 @@ func (row *TableRow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 }
 
 func (cell *TableCell) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	//chardata between child instructions becomes a text instruction, to keep the order of the flow
+	text := ""
+	flushText := func() {
+		if strings.TrimSpace(text) != "" {
+			cell.Instructions = append(cell.Instructions, &Text{Text: text})
+		}
+		text = ""
+	}
 	for {
 		token, err := d.Token()
 		if err != nil {
@@ -64,17 +73,36 @@ func (cell *TableCell) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		switch t := token.(type) {
 		case xml.EndElement:
 			if t == start.End() {
+				if len(cell.Instructions) > 0 {
+					flushText()
+				}
 				return nil
 			}
 		case xml.StartElement:
+			if t.Name.Local == "a" {
+				//links are written inline with the text around them
+				l := &Link{}
+				err := d.DecodeElement(l, &t)
+				if err != nil {
+					return err
+				}
+				cell.Content += l.markdown()
+				text += l.markdown()
+				continue
+			}
+			flushText()
 			i, err := instructionRegistry.Decode(d, t)
 			if err != nil {
 				Logf("decode cell instruction failed: %v", err)
+				if cell.decodeErr == nil {
+					cell.decodeErr = err
+				}
 				continue
 			}
 			cell.Instructions = append(cell.Instructions, i)
 		case xml.CharData:
 			cell.Content += string(t)
+			text += string(t)
 		}
 	}
 }
@@ -87,8 +115,10 @@ const (
 
 type Table struct {
 	Styled
-	XMLName xml.Name    `xml:"table"`
-	Rows    []*TableRow `xml:"tr"`
+	XMLName        xml.Name    `xml:"table"`
+	Rows           []*TableRow `xml:"tr"`
+	spansProcessed bool
+	spansErr       error
 }
 
 type TableRow struct {
@@ -106,25 +136,32 @@ type TableCell struct {
 	spannedBy      *TableCell
 	spans          []*TableCell
 	x0, y0, x1, y1 float64
+	//decodeErr is the first error decoding a child instruction, which is skipped
+	decodeErr error
+}
+
+// flow returns the instructions laid out in the cell. Cells with plain chardata flow a single text.
+func (cell *TableCell) flow() []Instruction {
+	if len(cell.Instructions) > 0 {
+		return cell.Instructions
+	}
+	return []Instruction{&Text{Text: cell.Content}}
 }
 
 func (p *Processor) ColumnWidths(t *Table, pageWidth float64, tableStyles style.Styles) []float64 {
+	if tableStyles.Table.Layout == style.TableLayoutAuto {
+		return p.autoColumnWidths(t, pageWidth, tableStyles)
+	}
 	cws := make([]float64, t.MaxColumnCount())
-	for _, row := range t.Rows {
-		rowStyles := tableStyles
-		row.Apply(p.doc.styleClasses, &rowStyles)
-		for i, c := range row.Cells {
-			cellStyles := rowStyles
-			c.Apply(p.doc.styleClasses, &cellStyles)
-			cw := float64(-1)
-			if cellStyles.Table.ColumnWidth > 0 {
-				cw = cellStyles.Table.ColumnWidth
-			}
-			if cw > 0 && cw > cws[i] {
-				cws[i] = cw
-			}
+	p.eachTableCell(t, tableStyles, func(col, span int, c *TableCell, cellStyles style.Styles) {
+		cw := float64(-1)
+		if cellStyles.Table.ColumnWidth > 0 {
+			cw = cellStyles.Table.ColumnWidth
 		}
-	}
+		if cw > 0 && cw > cws[col] {
+			cws[col] = cw
+		}
+	})
 	spaceUsed := float64(0)
 	columnsZero := float64(0)
 	for _, c := range cws {
@@ -145,6 +182,178 @@ func (p *Processor) ColumnWidths(t *Table, pageWidth float64, tableStyles style.
 	return cws
 }
 
+// eachTableCell calls fn for each cell with the column it starts in and the number of columns it spans. The
+// cells are styled like they're rendered.
+func (p *Processor) eachTableCell(t *Table, tableStyles style.Styles, fn func(col, span int, c *TableCell, cellStyles style.Styles)) {
+	colCount := t.MaxColumnCount()
+	for ir, row := range t.Rows {
+		p.eachRowCell(row, colCount, p.tableRowStyles(t, ir, tableStyles), fn)
+	}
+}
+
+// eachRowCell calls fn for each cell of row with the column it starts in and the number of columns it spans.
+func (p *Processor) eachRowCell(row *TableRow, colCount int, rowStyles style.Styles, fn func(col, span int, c *TableCell, cellStyles style.Styles)) {
+	col := 0
+	for ic, c := range row.Cells {
+		if col >= colCount {
+			break
+		}
+		cellStyles := p.tableCellStyles(row, ic, rowStyles)
+		span := cellStyles.Table.ColumnSpan
+		if span < 1 {
+			span = 1
+		}
+		if col+span > colCount {
+			span = colCount - col
+		}
+		fn(col, span, c, cellStyles)
+		col += span
+	}
+}
+
+// tableRowStyles returns the styles of row ir. The first and the last row are styled with their selectors.
+func (p *Processor) tableRowStyles(t *Table, ir int, tableStyles style.Styles) style.Styles {
+	row := t.Rows[ir]
+	if ir == 0 {
+		row.ApplyWithSelector("first", p.doc.styleClasses, &tableStyles)
+	} else if ir == len(t.Rows)-1 {
+		row.ApplyWithSelector("last", p.doc.styleClasses, &tableStyles)
+	} else {
+		row.Apply(p.doc.styleClasses, &tableStyles)
+	}
+	return tableStyles
+}
+
+// tableCellStyles returns the styles of cell ic of row. The first and the last cell are styled with their selectors.
+func (p *Processor) tableCellStyles(row *TableRow, ic int, rowStyles style.Styles) style.Styles {
+	c := row.Cells[ic]
+	if ic == 0 {
+		c.ApplyWithSelector("first", p.doc.styleClasses, &rowStyles)
+	} else if ic == len(row.Cells)-1 {
+		c.ApplyWithSelector("last", p.doc.styleClasses, &rowStyles)
+	} else {
+		c.Apply(p.doc.styleClasses, &rowStyles)
+	}
+	return rowStyles
+}
+
+// spanWidth returns the width of span columns starting at col.
+func spanWidth(colWs []float64, col, span int) float64 {
+	w := float64(0)
+	for _, cw := range colWs[col : col+span] {
+		w += cw
+	}
+	return w
+}
+
+// autoColumnWidths sizes the columns without explicit width between the minimal content width (widest word)
+// and the maximal content width (no wrapping) and distributes the available width proportionally.
+func (p *Processor) autoColumnWidths(t *Table, pageWidth float64, tableStyles style.Styles) []float64 {
+	colCount := t.MaxColumnCount()
+	fixed := make([]float64, colCount)
+	mins := make([]float64, colCount)
+	maxs := make([]float64, colCount)
+	type spanning struct {
+		col, span int
+		min, max  float64
+	}
+	spannings := []spanning{}
+	p.eachTableCell(t, tableStyles, func(col, span int, c *TableCell, cellStyles style.Styles) {
+		if c.spannedBy != nil {
+			return
+		}
+		if span == 1 && cellStyles.Table.ColumnWidth > 0 {
+			if cellStyles.Table.ColumnWidth > fixed[col] {
+				fixed[col] = cellStyles.Table.ColumnWidth
+			}
+			return
+		}
+		min, max := p.cellContentWidths(c, cellStyles)
+		if span > 1 {
+			spannings = append(spannings, spanning{col: col, span: span, min: min, max: max})
+			return
+		}
+		if min > mins[col] {
+			mins[col] = min
+		}
+		if max > maxs[col] {
+			maxs[col] = max
+		}
+	})
+
+	//widen auto columns to fit cells spanning several columns
+	for _, sp := range spannings {
+		autoCols := 0
+		sumMin, sumMax := float64(0), float64(0)
+		for col := sp.col; col < sp.col+sp.span; col++ {
+			if fixed[col] > 0 {
+				sumMin += fixed[col]
+				sumMax += fixed[col]
+				continue
+			}
+			autoCols++
+			sumMin += mins[col]
+			sumMax += maxs[col]
+		}
+		if autoCols == 0 {
+			continue
+		}
+		for col := sp.col; col < sp.col+sp.span; col++ {
+			if fixed[col] > 0 {
+				continue
+			}
+			if sp.min > sumMin {
+				mins[col] += (sp.min - sumMin) / float64(autoCols)
+			}
+			if sp.max > sumMax {
+				maxs[col] += (sp.max - sumMax) / float64(autoCols)
+			}
+		}
+	}
+
+	available := pageWidth
+	sumMin, sumMax := float64(0), float64(0)
+	autoCols := 0
+	for col := range fixed {
+		if fixed[col] > 0 {
+			available -= fixed[col]
+			continue
+		}
+		autoCols++
+		sumMin += mins[col]
+		sumMax += maxs[col]
+	}
+
+	cws := make([]float64, colCount)
+	for col := range cws {
+		switch {
+		case fixed[col] > 0:
+			cws[col] = fixed[col]
+		case sumMax <= 0:
+			cws[col] = available / float64(autoCols)
+		case available >= sumMax:
+			cws[col] = available * maxs[col] / sumMax
+		case available <= sumMin:
+			cws[col] = available * mins[col] / sumMin
+		default:
+			cws[col] = mins[col] + (maxs[col]-mins[col])*(available-sumMin)/(sumMax-sumMin)
+		}
+	}
+	return cws
+}
+
+// cellContentWidths returns the minimal and maximal width a cell needs for its content, including paddings.
+func (p *Processor) cellContentWidths(c *TableCell, cellStyles style.Styles) (float64, float64) {
+	saved := p.currStyles
+	p.currStyles = p.cellFlowStyles(cellStyles)
+	min, max := p.instructionsWidths(c.flow())
+	p.currStyles = saved
+	p.resetStyles()
+	//without 3 it doesn't fit, see renderCell
+	extra := cellStyles.Box.Padding.Left + cellStyles.Box.Padding.Right + 3
+	return min + extra, max + extra
+}
+
 func (t Table) MaxColumnCount() int {
 	m := 0
 	for _, row := range t.Rows {
@@ -156,6 +365,10 @@ func (t Table) MaxColumnCount() int {
 }
 
 func (p *Processor) processTableSpans(t *Table) error {
+	if t.spansProcessed {
+		return t.spansErr
+	}
+	t.spansProcessed = true
 	for ir, row := range t.Rows {
 		for ic, cell := range row.Cells {
 			var cellStyles style.Styles
@@ -165,7 +378,8 @@ func (p *Processor) processTableSpans(t *Table) error {
 				for n := 0; n <= cellStyles.RowSpan-2; n++ {
 					spannedRowIdx := ir + 1 + n
 					if spannedRowIdx >= len(t.Rows) {
-						return errors.Errorf("row span exceeds table")
+						t.spansErr = errors.Errorf("row span exceeds table")
+						return t.spansErr
 					}
 					spannedRow := t.Rows[spannedRowIdx]
 					if ic <= len(spannedRow.Cells) {
@@ -192,11 +406,8 @@ func (p *Processor) tableHeight(t *Table, tableStyles style.Styles) float64 {
 	if t.MaxColumnCount() == 0 {
 		return 0
 	}
-	cellHeight := func(c *TableCell, cellWidth float64, cellStyle style.Styles) float64 {
-		textWidth := cellWidth - cellStyle.Box.Padding.Left - cellStyle.Box.Padding.Right
-		height := p.textHeight(c.Content, textWidth, cellStyle.Dimension.LineHeight, cellStyle.Font)
-		return height + cellStyle.Box.Padding.Top + cellStyle.Box.Padding.Bottom
-	}
+	tableStyles = rowBaseStyles(tableStyles)
+	p.processTableSpans(t)
 
 	widthTotal, _ := p.pdf.GetPageSize()
 	leftM, _, rightM, _ := p.pdf.GetMargins()
@@ -204,30 +415,40 @@ func (p *Processor) tableHeight(t *Table, tableStyles style.Styles) float64 {
 	colWs := p.ColumnWidths(t, widthTotal, tableStyles)
 
 	totalHeight := float64(0)
-	for _, row := range t.Rows {
-		rowStyles := tableStyles
-		row.Apply(p.doc.styleClasses, &rowStyles)
-		//calc row height
-		rowHeight := float64(0)
-		for i, c := range row.Cells {
-			cellStyles := rowStyles
-			c.Apply(p.doc.styleClasses, &cellStyles)
-			ch := cellHeight(c, colWs[i], cellStyles)
-			if ch > rowHeight {
-				rowHeight = ch
-			}
-		}
-		totalHeight += rowHeight
+	for _, rh := range p.tableRowHeights(t, tableStyles, colWs) {
+		totalHeight += rh
 	}
 	return totalHeight
 }
 
+// tableRowHeights returns the heights of the rows. Row-spanned cells aren't considered, as their height might be
+// bigger than the rows they start in.
+func (p *Processor) tableRowHeights(t *Table, tableStyles style.Styles, colWs []float64) []float64 {
+	colCount := t.MaxColumnCount()
+	rhs := make([]float64, len(t.Rows))
+	for ir, row := range t.Rows {
+		p.eachRowCell(row, colCount, p.tableRowStyles(t, ir, tableStyles), func(col, span int, c *TableCell, cellStyles style.Styles) {
+			if len(c.spans) > 0 || c.spannedBy != nil {
+				return
+			}
+			ch := p.cellHeight(c, spanWidth(colWs, col, span), cellStyles)
+			if ch > rhs[ir] {
+				rhs[ir] = ch
+			}
+		})
+	}
+	return rhs
+}
+
 func (p *Processor) renderTable(t *Table, tableStyles style.Styles) {
 	if t.MaxColumnCount() == 0 {
 		return
 	}
-	p.processTableSpans(t)
-	tableHeight := p.tableHeight(t, tableStyles)
+	err := p.processTableSpans(t)
+	if err != nil {
+		p.problem(errors.Wrap(err, "process table spans"))
+	}
+	tableStyles = rowBaseStyles(tableStyles)
 
 	//if not further specified, distribute witdths uniformly
 	widthTotal, _ := p.pdf.GetPageSize()
@@ -235,92 +456,50 @@ func (p *Processor) renderTable(t *Table, tableStyles style.Styles) {
 	widthTotal -= (leftM + rightM)
 	colWs := p.ColumnWidths(t, widthTotal, tableStyles)
 
-	cellHeight := func(c *TableCell, cellIdx int, cellStyle style.Styles) float64 {
-		cellWidth := colWs[cellIdx]
-		if cellStyle.Table.ColumnSpan > 1 {
-			for cs := 1; cs < cellStyle.Table.ColumnSpan; cs++ {
-				if cellIdx+cs < len(colWs) {
-					cellWidth += colWs[cellIdx+cs]
-				}
-			}
-		}
-		textWidth := cellWidth - cellStyle.Box.Padding.Left - cellStyle.Box.Padding.Right
-		height := p.textHeight(c.Content, textWidth, cellStyle.Dimension.LineHeight, cellStyle.Font)
-		return height + cellStyle.Box.Padding.Top + cellStyle.Box.Padding.Bottom
+	rowHeights := p.tableRowHeights(t, tableStyles, colWs)
+	tableHeight := float64(0)
+	for _, rh := range rowHeights {
+		tableHeight += rh
 	}
 
 	_, ph := p.pdf.GetPageSize()
 	ph -= (bottomM)
 	x0 := p.pdf.GetX()
 	y := p.pdf.GetY()
+	//rows continue at the same indent, when the flow moves on to the next page or column
+	indent := x0 - leftM
+	addPage := func() {
+		p.addPage()
+		l, _, _, _ := p.pdf.GetMargins()
+		x0, y = l+indent, p.pdf.GetY()
+	}
 	if y+tableHeight > ph {
-		p.pdf.AddPage()
-		y = p.pdf.GetY()
+		addPage()
 	}
 
 	afterRender := []func(){}
 
-	for ir, row := range t.Rows {
-		rowStyles := tableStyles
-		if ir == 0 {
-			row.ApplyWithSelector("first", p.doc.styleClasses, &rowStyles)
-		} else if ir == len(t.Rows)-1 {
-			row.ApplyWithSelector("last", p.doc.styleClasses, &rowStyles)
-		} else {
-			row.Apply(p.doc.styleClasses, &rowStyles)
-		}
-		//calc row height
-		rowHeight := float64(0)
-		for i, c := range row.Cells {
-			if len(c.spans) > 0 || c.spannedBy != nil {
-				//don't consider row-spanned cells for general rowheight, as their height might by bigger
-				continue
-			}
-			cellStyles := rowStyles
-			c.Apply(p.doc.styleClasses, &cellStyles)
-			ch := cellHeight(c, i, cellStyles)
-			if ch > rowHeight {
-				rowHeight = ch
-			}
-		}
-
-		if y+rowHeight >= ph {
-			p.pdf.AddPage()
-			y = p.pdf.GetY()
-		}
-
+	//renderRow renders row at the current y. Repeated (header) rows are rendered self-contained, so they
+	//neither update cell bounds nor defer row-spanned cells.
+	renderRow := func(row *TableRow, rowStyles style.Styles, rowHeight float64, repeated bool) {
 		x := x0
-		colOffset := 0
-		for ic, c := range row.Cells {
-			cellStyles := rowStyles
-			if ic == 0 {
-				c.ApplyWithSelector("first", p.doc.styleClasses, &cellStyles)
-			} else if ic == len(row.Cells) {
-				c.ApplyWithSelector("last", p.doc.styleClasses, &cellStyles)
-			} else {
-				c.Apply(p.doc.styleClasses, &cellStyles)
-			}
-
+		p.eachRowCell(row, len(colWs), rowStyles, func(col, span int, c *TableCell, cellStyles style.Styles) {
 			p.pdf.SetXY(x, y)
 			x0 := x
 			y0 := y
-			ws := colWs[colOffset]
+			ws := spanWidth(colWs, col, span)
 			x1 := x + ws
-			if cellStyles.Table.ColumnSpan > 1 {
-				for cs := 1; cs < cellStyles.Table.ColumnSpan; cs++ {
-					if colOffset+cs < len(colWs) {
-						x1 += colWs[colOffset+cs]
-						ws += colWs[colOffset+cs]
-					}
+			y1 := y + rowHeight
+			x += ws
+			if repeated {
+				if c.spannedBy == nil {
+					p.renderCell(x0, y0, x1, y1, c, cellStyles)
 				}
+				return
 			}
-			colOffset += cellStyles.Table.ColumnSpan
-
-			y1 := y + rowHeight
 			c.x0, c.y0, c.x1, c.y1 = x0, y0, x1, y1
-			x += ws
 			if c.spannedBy != nil {
-				continue
+				return
 			}
 			if len(c.spans) > 0 {
 				arStyles := cellStyles
@@ -335,53 +514,112 @@ func (p *Processor) renderTable(t *Table, tableStyles style.Styles) {
 					}
 					p.renderCell(arCell.x0, arCell.y0, arCell.x1, y1, arCell, arStyles)
 				})
-				continue
+				return
 			}
 			p.renderCell(x0, y0, x1, y1, c, cellStyles)
-		}
+		})
 		y += rowHeight
 		p.pdf.Ln(-1)
 	}
 
+	//header rows are marked by their own styles, repeat-header of the table doesn't apply to its rows
+	isHeaderRow := func(ir int) bool {
+		rs := tableStyles
+		rs.Table.RepeatHeader = false
+		return p.tableRowStyles(t, ir, rs).Table.RepeatHeader
+	}
+	headerRows := 0
+	for ir := range t.Rows {
+		if !isHeaderRow(ir) {
+			break
+		}
+		headerRows++
+	}
+
+	for ir, row := range t.Rows {
+		rh := rowHeights[ir]
+		if y+rh >= ph {
+			addPage()
+			if ir >= headerRows {
+				for ih, hrow := range t.Rows[:headerRows] {
+					renderRow(hrow, p.tableRowStyles(t, ih, tableStyles), rowHeights[ih], true)
+				}
+			}
+		}
+		renderRow(row, p.tableRowStyles(t, ir, tableStyles), rh, false)
+	}
+
 	for _, ar := range afterRender {
 		ar()
 	}
 
 	p.pdf.SetXY(x0, y)
-	p.pdf.Ln(-1)
+	p.pdf.Ln(p.lineHeight(tableStyles))
 }
 
-func (p *Processor) renderCell(x0, y0, x1, y1 float64, c *TableCell, cellStyles style.Styles) {
-	p.drawBox(x0, y0, x1, y1, cellStyles)
+// cellFlowStyles returns the styles the instructions in a cell start with. Only text related styles are
+// inherited from the cell, box styles like borders and paddings belong to the cell itself.
+func (p *Processor) cellFlowStyles(cellStyles style.Styles) style.Styles {
+	sty := p.currStyles
+	sty.Font = cellStyles.Font
+	sty.Color.Text = cellStyles.Color.Text
+	sty.Align = cellStyles.Align
+	sty.Dimension.LineHeight = cellStyles.Dimension.LineHeight
+	sty.Wrap = cellStyles.Wrap
+	return sty
+}
 
-	textWidth := (x1 - x0) - cellStyles.Box.Padding.Left - cellStyles.Box.Padding.Right - 3 //wihout 2 it doesn't fit
-	textHeight := p.textHeight(c.Content, textWidth, cellStyles.Dimension.LineHeight, cellStyles.Font)
-	textMargin := y1 - y0 - textHeight - cellStyles.Box.Padding.Top - cellStyles.Box.Padding.Bottom
-	if textMargin < 0 {
-		textMargin = 0
-	}
+// withCellFlow runs fn in the frame of the cell's content area, starting with the cell's flow styles.
+func (p *Processor) withCellFlow(x0, cellWidth float64, cellStyles style.Styles, fn func()) {
+	saved, savedInCell := p.currStyles, p.inCell
+	p.currStyles = p.cellFlowStyles(cellStyles)
+	p.inCell = true
+	p.resetStyles()
+	p.withFrame(x0+cellStyles.Box.Padding.Left, cellWidth-cellStyles.Box.Padding.Left-cellStyles.Box.Padding.Right, fn)
+	p.currStyles, p.inCell = saved, savedInCell
+	p.resetStyles()
+}
 
-	if cellStyles.Align.VAlign == style.VAlignMiddle {
-		p.pdf.SetY(y0 + cellStyles.Box.Padding.Top + textMargin/2)
-	} else if cellStyles.Align.VAlign == style.VAlignBottom {
-		p.pdf.SetY(y0 + cellStyles.Box.Padding.Top + textMargin)
-	} else {
-		p.pdf.SetY(y0 + cellStyles.Box.Padding.Top)
+// rowBaseStyles returns the table styles the rows start with. Only a height set on a row or a cell fixes the height
+// of the cells, a height of the table or further out isn't inherited.
+func rowBaseStyles(tableStyles style.Styles) style.Styles {
+	tableStyles.Dimension.Height = -1
+	return tableStyles
+}
+
+// cellHeight returns the height of the cell including paddings. Cells with a height style of their own or of their
+// row have a fixed height.
+func (p *Processor) cellHeight(c *TableCell, cellWidth float64, cellStyles style.Styles) float64 {
+	if cellStyles.Dimension.Height >= 0 {
+		return cellStyles.Dimension.Height + cellStyles.Box.Padding.Top + cellStyles.Box.Padding.Bottom
 	}
-	p.pdf.SetX(x0 + cellStyles.Box.Padding.Left)
+	var height float64
+	l, _, _, _ := p.pdf.GetMargins()
+	p.withCellFlow(l, cellWidth, cellStyles, func() {
+		height = p.instructionsHeight(c.flow())
+	})
+	return height + cellStyles.Box.Padding.Top + cellStyles.Box.Padding.Bottom
+}
 
-	p.write(c.Content, textWidth, cellStyles.Dimension.LineHeight, cellStyles.Align.HAlign, cellStyles.Font, cellStyles.Color.Text)
+func (p *Processor) renderCell(x0, y0, x1, y1 float64, c *TableCell, cellStyles style.Styles) {
+	p.drawBox(x0, y0, x1, y1, cellStyles)
 
-	for _, inst := range c.Instructions {
-		switch inst := inst.(type) {
-		case *Box:
-			p.pdf.SetY(y0 + cellStyles.Box.Padding.Top)
-			p.pdf.SetX(x0 + cellStyles.Box.Padding.Left)
-			p.renderTextBox(inst.Text, p.appliedStyles(inst))
-		case *Image:
+	p.withCellFlow(x0, x1-x0, cellStyles, func() {
+		contentHeight := p.instructionsHeight(c.flow())
+		contentMargin := y1 - y0 - contentHeight - cellStyles.Box.Padding.Top - cellStyles.Box.Padding.Bottom
+		if contentMargin < 0 {
+			contentMargin = 0
+		}
+
+		if cellStyles.Align.VAlign == style.VAlignMiddle {
+			p.pdf.SetY(y0 + cellStyles.Box.Padding.Top + contentMargin/2)
+		} else if cellStyles.Align.VAlign == style.VAlignBottom {
+			p.pdf.SetY(y0 + cellStyles.Box.Padding.Top + contentMargin)
+		} else {
 			p.pdf.SetY(y0 + cellStyles.Box.Padding.Top)
-			p.pdf.SetX(x0 + cellStyles.Box.Padding.Left)
-			p.renderImage(inst, p.appliedStyles(inst))
 		}
-	}
+		p.withOverflowBottom(cellStyles.Dimension.Height >= 0, y1-cellStyles.Box.Padding.Bottom, func() {
+			p.processInstructions(Instructions{iss: c.flow()})
+		})
+	})
 }
@@ -0,0 +1,226 @@
+package gompdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+var pdfStreamRx = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// pdfContent returns the inflated streams of a pdf.
+func pdfContent(t *testing.T, b []byte) string {
+	t.Helper()
+	content := ""
+	for _, m := range pdfStreamRx.FindAllSubmatch(b, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			continue
+		}
+		d, err := ioutil.ReadAll(r)
+		if err != nil {
+			continue
+		}
+		content += string(d)
+	}
+	return content
+}
+
+func TestRepeatHeaderOfRows(t *testing.T) {
+	rows := []string{`<tr style="repeat-header: true"><td>HEAD</td></tr>`}
+	for i := 0; i < 120; i++ {
+		rows = append(rows, fmt.Sprintf("<tr><td>r%03d</td></tr>", i))
+	}
+	for _, tableStyle := range []string{"", "repeat-header: true"} {
+		src := `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body><table style="` + tableStyle + `">` + strings.Join(rows, "") + `</table></body>
+</document>`
+		content := pdfContent(t, processTestSource(t, src))
+		pages := strings.Count(content, "(r000)") + strings.Count(content, "(r119)")
+		if pages != 2 {
+			t.Fatalf("table style (%s): want the first and last row once, got %d", tableStyle, pages)
+		}
+		heads := strings.Count(content, "(HEAD)")
+		if heads < 3 {
+			t.Errorf("table style (%s): want the header repeated on each page, got %d", tableStyle, heads)
+		}
+	}
+}
+
+func TestTableHeightOfFixedCells(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit></default>
+<body>
+<table style="height: 50"><tr><td>a</td></tr><tr><td>b</td></tr></table>
+<table style="height: 50"><tr style="height: 20"><td>a</td></tr><tr><td style="height: 10">b</td></tr></table>
+</body>
+</document>`)
+	tab := p.doc.Body.iss[0].(*Table)
+	flowing := p.tableHeight(tab, p.appliedStyles(tab))
+	if flowing <= 0 || flowing >= 50 {
+		t.Errorf("want the table's height not to fix its cells, got table height %v", flowing)
+	}
+	tab = p.doc.Body.iss[1].(*Table)
+	if h := p.tableHeight(tab, p.appliedStyles(tab)); math.Abs(h-30) > 0.01 {
+		t.Errorf("want table height 30 of the row's and cell's heights, got %v", h)
+	}
+}
+
+func TestTableHeightOfSpannedCellsAndRowSelectors(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format></default>
+<style>
+row { font-point-size: 10 }
+row:first { font-point-size: 30 }
+row:last { font-point-size: 20 }
+</style>
+<body>
+<table>
+<tr class="row"><td style="column-width: 20">a</td><td style="column-width: 100">b</td><td style="column-width: 20">c</td></tr>
+<tr class="row"><td style="column-span: 2">spanned</td><td>some words, that wrap in the narrow column</td></tr>
+<tr class="row"><td>a</td><td>b</td><td>c</td></tr>
+</table>
+</body>
+</document>`)
+	tab := p.doc.Body.iss[0].(*Table)
+	rendered := tab.Rows[2].Cells[0].y1 - tab.Rows[0].Cells[0].y0
+	if h := p.tableHeight(tab, p.appliedStyles(tab)); math.Abs(h-rendered) > 0.01 {
+		t.Errorf("want measured table height %v to be the rendered height %v", h, rendered)
+	}
+	first, last := tab.Rows[0].Cells[0], tab.Rows[2].Cells[0]
+	if first.y1-first.y0 <= last.y1-last.y0 {
+		t.Errorf("want the first row styled by its selector higher than the last row, got %v and %v",
+			first.y1-first.y0, last.y1-last.y0)
+	}
+	//the wrapping cell starts in the third column, not in the second one
+	spanned, wrapping := tab.Rows[1].Cells[0], tab.Rows[1].Cells[1]
+	if math.Abs(spanned.x1-spanned.x0-120) > 0.01 || math.Abs(wrapping.x1-wrapping.x0-20) > 0.01 {
+		t.Errorf("want cell widths 120 and 20, got %v and %v", spanned.x1-spanned.x0, wrapping.x1-wrapping.x0)
+	}
+}
+
+func TestAutoColumnWidths(t *testing.T) {
+	p := loadTestProcessor(t, `<document>
+<default><unit>mm</unit><format>a5</format></default>
+<body>
+<table style="table-layout: auto">
+<tr><td>a</td><td>a longer content</td><td style="column-width: 30">fixed</td></tr>
+<tr><td style="column-span: 2">a spanning cell with much more content than both columns need on their own</td><td>c</td></tr>
+</table>
+<table style="table-layout: auto">
+<tr><td>a</td><td>a longer content</td></tr>
+</table>
+</body>
+</document>`)
+	tab := p.doc.Body.iss[0].(*Table)
+	cws := p.ColumnWidths(tab, 148, rowBaseStyles(p.appliedStyles(tab)))
+	if len(cws) != 3 {
+		t.Fatalf("want 3 column widths, got %v", cws)
+	}
+	if math.Abs(cws[2]-30) > 0.01 {
+		t.Errorf("want the fixed column width 30, got %v", cws[2])
+	}
+	if math.Abs(cws[0]+cws[1]+cws[2]-148) > 0.01 {
+		t.Errorf("want the columns to fill the width 148, got %v", cws)
+	}
+	if cws[1] <= cws[0] {
+		t.Errorf("want the column with longer content wider, got %v", cws)
+	}
+
+	//without the spanning cell the columns share the width by their content widths
+	single := p.doc.Body.iss[1].(*Table)
+	scws := p.ColumnWidths(single, 148, rowBaseStyles(p.appliedStyles(single)))
+	if math.Abs(scws[0]+scws[1]-148) > 0.01 {
+		t.Errorf("want the columns to fill the width 148, got %v", scws)
+	}
+	if scws[1] <= 4*scws[0] {
+		t.Errorf("want the column with much longer content much wider, got %v", scws)
+	}
+	//the spanning cell's content widens both columns it spans evenly
+	if cws[0]/(cws[0]+cws[1]) <= scws[0]/(scws[0]+scws[1]) {
+		t.Errorf("want the spanning cell to widen the narrow column, got %v and without it %v", cws[:2], scws)
+	}
+}
+
+func TestCellFlow(t *testing.T) {
+	src := `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>
+<table><tr>
+<td style="padding: 4,2,0,3; font-point-size: 20"><text>first</text><text>second</text><rect width="10" height="8"/></td>
+<td style="padding: 0,0,0,0">plain</td>
+</tr></table>
+<table><tr><td style="padding: 0,0,0,0"><table><tr><td style="padding: 0,1,0,1">inner</td></tr><tr><td style="padding: 0,1,0,1">rows</td></tr></table></td></tr></table>
+</body>
+</document>`
+	p := loadTestProcessor(t, src)
+	tab := p.doc.Body.iss[0].(*Table)
+	flow, plain := tab.Rows[0].Cells[0], tab.Rows[0].Cells[1]
+	sty := p.appliedStyles(tab)
+	sty.Font.PointSize = 20
+	//the instructions of the cell flow one below the other, in the font of the cell and within its paddings
+	want := 2*p.lineHeight(sty) + 8 + 2 + 3
+	if h := flow.y1 - flow.y0; math.Abs(h-want) > 0.01 {
+		t.Errorf("want the cell as high as its flow %v, got %v", want, h)
+	}
+	if plain.y1-plain.y0 != flow.y1-flow.y0 {
+		t.Errorf("want the cells of a row equally high, got %v and %v", plain.y1-plain.y0, flow.y1-flow.y0)
+	}
+
+	nested := p.doc.Body.iss[1].(*Table)
+	inner := nested.Rows[0].Cells[0].Instructions[0].(*Table)
+	//like in the body, a line follows the nested table
+	innerHeight := inner.Rows[1].Cells[0].y1 - inner.Rows[0].Cells[0].y0 + p.lineHeight(p.appliedStyles(nested))
+	if math.Abs(nested.Rows[0].Cells[0].y1-nested.Rows[0].Cells[0].y0-innerHeight) > 0.01 {
+		t.Errorf("want the cell as high as the nested table %v, got %v", innerHeight, nested.Rows[0].Cells[0].y1-nested.Rows[0].Cells[0].y0)
+	}
+
+	//texts are written at the left padding of their cells, with the cell margin of fpdf
+	k := 72 / 25.4
+	content := pdfContent(t, processTestSource(t, src))
+	for _, w := range []struct {
+		text string
+		x    float64
+	}{
+		{text: "first", x: flow.x0 + 4},
+		{text: "second", x: flow.x0 + 4},
+		{text: "plain", x: plain.x0},
+		{text: "inner", x: nested.Rows[0].Cells[0].x0},
+	} {
+		m := regexp.MustCompile(`BT ([0-9.]+) [0-9.]+ Td \(` + w.text + `\)Tj`).FindStringSubmatch(content)
+		if m == nil {
+			t.Errorf("want (%s) written", w.text)
+			continue
+		}
+		x, _ := strconv.ParseFloat(m[1], 64)
+		if math.Abs(x/k-w.x-p.pdf.GetCellMargin()) > 0.05 {
+			t.Errorf("want (%s) at %v, got %v", w.text, w.x, x/k-p.pdf.GetCellMargin())
+		}
+	}
+}
+
+func TestCellFlowStyles(t *testing.T) {
+	p := loadTestProcessor(t, `<document><body/></document>`)
+	cellStyles := p.currStyles
+	cellStyles.Font.PointSize = 33
+	cellStyles.Align.HAlign = style.HAlignRight
+	cellStyles.Box.Padding = style.Padding{Left: 5, Top: 5, Right: 5, Bottom: 5}
+	cellStyles.Box.Border = style.Border{Left: 1, Top: 1, Right: 1, Bottom: 1}
+	sty := p.cellFlowStyles(cellStyles)
+	if sty.Font.PointSize != 33 || sty.Align.HAlign != style.HAlignRight {
+		t.Errorf("want the text styles inherited from the cell, got font size %v and h-align %v", sty.Font.PointSize, sty.Align.HAlign)
+	}
+	if !reflect.DeepEqual(sty.Box, p.currStyles.Box) {
+		t.Errorf("want the box styles not inherited from the cell, got %v", sty.Box)
+	}
+}
@@ -0,0 +1,99 @@
+package gompdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// lineMarkerRx matches the markers of template lines in the marked output of a template.
+var lineMarkerRx = regexp.MustCompile("\x00([0-9]+)\x00")
+
+// templateLine maps line of the executed template output out back to the template line it starts in. To do so the
+// template is executed again, with the template lines marked in its text. It returns 0, if the line can't be mapped,
+// e.g. because the marked output differs from out.
+func templateLine(name, src string, data interface{}, funcs template.FuncMap, out []byte, line int) int {
+	tmpl, err := template.New(name).Funcs(funcs).Parse(src)
+	if err != nil {
+		return 0
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			markTemplateLines(t.Tree, t.Tree.Root)
+		}
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+	if err != nil {
+		return 0
+	}
+	marked := buf.Bytes()
+	if !bytes.Equal(lineMarkerRx.ReplaceAll(marked, nil), out) {
+		return 0
+	}
+	start := 0
+	for n := 1; n < line; n++ {
+		i := bytes.IndexByte(marked[start:], '\n')
+		if i < 0 {
+			return 0
+		}
+		start += i + 1
+	}
+	//the last marker before the start of the line; lines started by actions belong to the line of the action
+	tl := 0
+	for _, m := range lineMarkerRx.FindAllSubmatchIndex(marked, -1) {
+		if m[0] > start {
+			break
+		}
+		tl, _ = strconv.Atoi(string(marked[m[2]:m[3]]))
+	}
+	return tl
+}
+
+// markTemplateLines inserts markers of their template lines into the text nodes below n, at their start and after
+// each newline.
+func markTemplateLines(tree *parse.Tree, n parse.Node) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			markTemplateLines(tree, c)
+		}
+	case *parse.IfNode:
+		markTemplateLines(tree, n.List)
+		markTemplateLines(tree, n.ElseList)
+	case *parse.RangeNode:
+		markTemplateLines(tree, n.List)
+		markTemplateLines(tree, n.ElseList)
+	case *parse.WithNode:
+		markTemplateLines(tree, n.List)
+		markTemplateLines(tree, n.ElseList)
+	case *parse.TextNode:
+		//the location is name:line:col
+		loc, _ := tree.ErrorContext(n)
+		parts := strings.Split(loc, ":")
+		if len(parts) < 3 {
+			return
+		}
+		line, err := strconv.Atoi(parts[len(parts)-2])
+		if err != nil {
+			return
+		}
+		buf := &bytes.Buffer{}
+		fmt.Fprintf(buf, "\x00%d\x00", line)
+		for _, b := range n.Text {
+			buf.WriteByte(b)
+			if b == '\n' {
+				line++
+				fmt.Fprintf(buf, "\x00%d\x00", line)
+			}
+		}
+		n.Text = buf.Bytes()
+	}
+}
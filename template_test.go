@@ -0,0 +1,47 @@
+package gompdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoadTemplateErrorLine(t *testing.T) {
+	src := `<document>
+<body>
+{{- range .}}
+<text>
+{{.}}
+</text>
+{{- end}}
+<box>unclosed
+</body>
+</document>`
+	_, err := LoadTemplate(bytes.NewBufferString(src), []string{"a", "b\nc", "d"}, nil)
+	if err == nil {
+		t.Fatalf("want decode error")
+	}
+	if !strings.Contains(err.Error(), "at template line 9 (output line 14") {
+		t.Errorf("want error at template line 9, got %v", err)
+	}
+
+	tests := []struct {
+		line, templateLine int
+	}{
+		{line: 1, templateLine: 1},
+		{line: 3, templateLine: 4},
+		{line: 4, templateLine: 5},
+		{line: 6, templateLine: 4},
+		{line: 7, templateLine: 5},
+		//the second line of "b\nc" belongs to the action
+		{line: 8, templateLine: 5},
+		{line: 9, templateLine: 6},
+		{line: 13, templateLine: 8},
+	}
+	out := "<document>\n<body>\n<text>\na\n</text>\n<text>\nb\nc\n</text>\n<text>\nd\n</text>\n<box>unclosed\n</body>\n</document>"
+	for _, test := range tests {
+		if tl := templateLine("t", src, []string{"a", "b\nc", "d"}, nil, []byte(out), test.line); tl != test.templateLine {
+			t.Errorf("output line %d: want template line %d, got %d", test.line, test.templateLine, tl)
+		}
+	}
+}
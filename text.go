@@ -1,8 +1,10 @@
 package gompdf
 
 import (
+	"sort"
 	"strings"
 
+	"github.com/mazzegi/gompdf/hyphenation"
 	"github.com/mazzegi/gompdf/markdown"
 	"github.com/mazzegi/gompdf/style"
 )
@@ -35,55 +37,131 @@ type textLine struct {
 	mdWords               markdown.Items
 	textWidth             float64
 	textWidthTrimmedRight float64
+	//forcedBreak is set, if the line ends with a newline instead of being wrapped
+	forcedBreak bool
+	//x is the offset of the line from the start of the text, width the width available for it. Beside floats
+	//words aren't broken, but the line is left blank, if the first word doesn't fit.
+	x      float64
+	width  float64
+	beside bool
+	blank  bool
+}
+
+// occupied returns true, if the line takes vertical space.
+func (l textLine) occupied() bool {
+	return len(l.mdWords) > 0 || l.blank
+}
+
+// gaps returns the number of word gaps in the line.
+func (l textLine) gaps() int {
+	n := 0
+	for i, mdWord := range l.mdWords {
+		if i < len(l.mdWords)-1 && strings.HasSuffix(mdWord.Text, " ") {
+			n++
+		}
+	}
+	return n
 }
 
 func (p *Processor) applyMarkdownFont(mdi markdown.Item, toFnt style.Font) {
 	fntStyles := fpdfFontStyle(toFnt)
-	if mdi.Italic {
+	if mdi.Italic && !strings.Contains(fntStyles, "I") {
 		fntStyles += "I"
 	}
-	if mdi.Bold {
+	if mdi.Bold && !strings.Contains(fntStyles, "B") {
 		fntStyles += "B"
 	}
 	family := string(toFnt.Family)
-	if mdi.Code {
+	if _, utf8 := p.utf8Fonts[strings.ToLower(family)]; mdi.Code && !utf8 {
+		//embedded fonts keep their family, as the text isn't translated for a core font
 		family = "Courier"
 	}
-	p.pdf.SetFont(family, fntStyles, toFnt.PointSize)
+	p.setFont(family, fntStyles, toFnt.PointSize)
 }
 
-func (p *Processor) textLines(mdWords markdown.Items, width float64, fnt style.Font) []textLine {
+func (p *Processor) textLines(mdWords markdown.Items, width float64, sty style.Styles) []textLine {
+	return p.wrapLines(mdWords, func(int) (float64, float64, bool) { return 0, width, false }, sty)
+}
+
+// wrapLines breaks the words into lines. span returns the offset (from the start of the text) and width of the
+// n-th occupied line and whether it is beside a float.
+func (p *Processor) wrapLines(mdWords markdown.Items, span func(n int) (float64, float64, bool), sty style.Styles) []textLine {
+	enc := p.encoder(sty.Font)
 	lines := []textLine{}
-	currLine := textLine{
-		mdWords:   markdown.Items{},
-		textWidth: 0.0,
+	occupied := 0
+	startLine := func() textLine {
+		x, width, beside := span(occupied)
+		return textLine{
+			mdWords:   markdown.Items{},
+			textWidth: 0,
+			x:         x,
+			width:     width,
+			beside:    beside,
+		}
+	}
+	currLine := startLine()
+	newLine := func() {
+		lines = append(lines, currLine)
+		if currLine.occupied() {
+			occupied++
+		}
+		currLine = startLine()
+	}
+	addWord := func(mdWord markdown.Item) {
+		if len(currLine.mdWords) == 0 {
+			mdWord.Text = strings.TrimLeft(mdWord.Text, " ")
+		}
+		currLine.mdWords = append(currLine.mdWords, mdWord)
+		currLine.textWidthTrimmedRight = currLine.textWidth + p.pdf.GetStringWidth(enc(strings.TrimRight(mdWord.Text, " ")))
+		currLine.textWidth += p.pdf.GetStringWidth(enc(mdWord.Text))
 	}
 	for _, mdWord := range mdWords {
 		if mdWord.Newline {
-			lines = append(lines, currLine)
-			currLine = textLine{
-				mdWords:   markdown.Items{},
-				textWidth: 0,
-			}
+			currLine.forcedBreak = true
+			newLine()
 			continue
 		}
-		p.applyMarkdownFont(mdWord, fnt)
-		wordWidth := p.pdf.GetStringWidth(mdWord.Text)
-		wordWidthTrimmedRight := p.pdf.GetStringWidth(strings.TrimRight(mdWord.Text, " "))
-		if currLine.textWidth+wordWidth > width {
-			lines = append(lines, currLine)
-			currLine = textLine{
-				mdWords:   markdown.Items{},
-				textWidth: 0,
+		p.applyMarkdownFont(mdWord, sty.Font)
+		//words, which don't fit, are hyphenated or moved to the next line. Words wider than the line are broken
+		//at any character, unless overflow-wrap is normal.
+		for {
+			text := mdWord.Text
+			if len(currLine.mdWords) == 0 {
+				text = strings.TrimLeft(text, " ")
 			}
+			if currLine.textWidth+p.pdf.GetStringWidth(enc(text)) <= currLine.width {
+				break
+			}
+			if len(currLine.mdWords) == 0 && currLine.beside && p.pdf.GetStringWidth(enc(strings.TrimRight(text, " "))) > currLine.width {
+				//the word moves down, until it fits beside or below the floats
+				currLine.blank = true
+				newLine()
+				continue
+			}
+			if currLine.width-currLine.textWidth <= 0 {
+				//without any space left, words aren't broken, but start a new line or overflow an empty one
+				if len(currLine.mdWords) == 0 {
+					break
+				}
+				newLine()
+				continue
+			}
+			head, tail := p.breakWord(text, currLine.width-currLine.textWidth, sty, len(currLine.mdWords) == 0)
+			if head == "" {
+				if len(currLine.mdWords) == 0 {
+					//overflows
+					break
+				}
+				newLine()
+				continue
+			}
+			headWord := mdWord
+			headWord.Text = head
+			addWord(headWord)
+			mdWord.Text = tail
+			newLine()
 		}
-		if len(currLine.mdWords) == 0 {
-			mdWord.Text = strings.TrimLeft(mdWord.Text, " ")
-			wordWidth = p.pdf.GetStringWidth(mdWord.Text)
-		}
-		currLine.mdWords = append(currLine.mdWords, mdWord)
-		currLine.textWidthTrimmedRight = currLine.textWidth + wordWidthTrimmedRight
-		currLine.textWidth += wordWidth
+		addWord(mdWord)
 	}
 	if len(currLine.mdWords) > 0 {
 		lines = append(lines, currLine)
@@ -97,45 +175,211 @@ func (p *Processor) textLines(mdWords markdown.Items, width float64, fnt style.F
 	return lines
 }
 
-func (p *Processor) write(text string, width float64, lineHeight float64, halign style.HAlign, fnt style.Font, cr style.RGB) {
-	p.applyFont(fnt)
+// breakWord splits word into a head, which fits into width, and the remaining tail. It breaks at hyphens and,
+// if enabled, hyphenation points. With word-break break-all, or if the word is the first on its line and
+// overflow-wrap is anywhere, it breaks at the last fitting character. The head is empty, if nothing fits.
+func (p *Processor) breakWord(word string, width float64, sty style.Styles, firstOnLine bool) (string, string) {
+	enc := p.encoder(sty.Font)
+	text := strings.TrimRight(word, " ")
+	rs := []rune(text)
+	if len(rs) == 0 {
+		return "", word
+	}
+	if sty.Wrap.WordBreak != style.WordBreakBreakAll {
+		points := []int{}
+		if h, ok := hyphenation.ForLanguage(string(sty.Wrap.Hyphenate)); ok {
+			points = h.Hyphenate(text)
+		}
+		for i, r := range rs {
+			if r == '-' && i > 0 && i < len(rs)-1 {
+				points = append(points, i+1)
+			}
+		}
+		sort.Ints(points)
+		for i := len(points) - 1; i >= 0; i-- {
+			head := string(rs[:points[i]])
+			if !strings.HasSuffix(head, "-") {
+				head += "-"
+			}
+			if p.pdf.GetStringWidth(enc(head)) <= width {
+				return head, word[len(string(rs[:points[i]])):]
+			}
+		}
+		if !firstOnLine || sty.Wrap.OverflowWrap != style.OverflowWrapAnywhere {
+			return "", word
+		}
+	}
+	n := 0
+	for n < len(rs) && p.pdf.GetStringWidth(enc(string(rs[:n+1]))) <= width {
+		n++
+	}
+	if n == 0 {
+		if !firstOnLine {
+			return "", word
+		}
+		//at least one character per line
+		n = 1
+	}
+	return string(rs[:n]), word[len(string(rs[:n])):]
+}
+
+// textItems returns the markdown word items of text with replaced placeholders.
+func (p *Processor) textItems(text string) markdown.Items {
+	return markdown.NewProcessor().Process(p.normalizedText(text)).WordItems(p.replacePlaceholders)
+}
+
+func (p *Processor) write(text string, width float64, sty style.Styles) {
+	p.writeItems(p.textItems(text), width, sty)
+}
+
+func (p *Processor) writeItems(mdWords markdown.Items, width float64, sty style.Styles) {
+	p.applyFont(sty.Font)
+	cr := sty.Color.Text
 	p.pdf.SetTextColor(int(cr.R), int(cr.G), int(cr.B))
-	text = p.normalizedText(text)
 	_, fontHeight := p.pdf.GetFontSize()
-	height := fontHeight * lineHeight
-	xLeft := p.pdf.GetX()
-	mdWords := markdown.NewProcessor().Process(text).WordItems(p.transformText)
-	lines := p.textLines(mdWords, width, fnt)
-	for _, line := range lines {
-		if len(line.mdWords) == 0 {
+	height := fontHeight * sty.Dimension.LineHeight
+	//lines start relative to the left margin, which moves, when the text continues in another column
+	lMargin, _, _, _ := p.pdf.GetMargins()
+	indent := p.pdf.GetX() - lMargin
+	enc := p.encoder(sty.Font)
+	lines := p.wrapLines(mdWords, p.floatSpans(p.pdf.GetX(), width, height, -1), sty)
+	pageBreaks := map[int]bool{}
+	if p.overflowBottom > 0 && sty.Wrap.Overflow != style.OverflowVisible {
+		lines = p.visibleLines(lines, height, width, sty)
+	} else if p.overflowBottom == 0 {
+		breaks := p.linePageBreaks(nonEmptyLines(lines), height, sty)
+		if len(p.floats) > 0 && len(breaks) > 0 {
+			//lines moved to the next page don't wrap around the floats. The lines before the first break stay,
+			//the following ones are wrapped again and may break differently.
+			first := breaks[0]
+			lines = p.wrapLines(mdWords, p.floatSpans(p.pdf.GetX(), width, height, first), sty)
+			rewrapped := []int{first}
+			for _, b := range p.linePageBreaks(nonEmptyLines(lines), height, sty) {
+				if b > first {
+					rewrapped = append(rewrapped, b)
+				}
+			}
+			breaks = rewrapped
+		}
+		for _, b := range breaks {
+			pageBreaks[b] = true
+		}
+	}
+	written := 0
+	for il, line := range lines {
+		if !line.occupied() {
+			continue
+		}
+		if pageBreaks[written] {
+			p.addPage()
+		}
+		written++
+		if line.blank {
+			p.pdf.Ln(height)
 			continue
 		}
-		switch halign {
+		lMargin, _, _, _ = p.pdf.GetMargins()
+		xLeft := lMargin + indent + line.x
+		gapWidth := float64(0)
+		switch sty.Align.HAlign {
 		case style.HAlignLeft:
 			p.pdf.SetX(xLeft)
+		case style.HAlignJustify:
+			p.pdf.SetX(xLeft)
+			if gaps := line.gaps(); gaps > 0 && il < len(lines)-1 && !line.forcedBreak {
+				gapWidth = (line.width - line.textWidthTrimmedRight) / float64(gaps)
+			}
 		case style.HAlignCenter:
-			p.pdf.SetX(xLeft + (width-line.textWidthTrimmedRight)/2.0)
+			p.pdf.SetX(xLeft + (line.width-line.textWidthTrimmedRight)/2.0)
 		case style.HAlignRight:
-			p.pdf.SetX(xLeft + width - line.textWidthTrimmedRight)
+			p.pdf.SetX(xLeft + line.width - line.textWidthTrimmedRight)
 		}
 
 		for _, mdWord := range line.mdWords {
-			p.applyMarkdownFont(mdWord, fnt)
-			p.pdf.Write(height, mdWord.Text)
+			p.applyMarkdownFont(mdWord, sty.Font)
+			mdWord.Text = enc(mdWord.Text)
+			if mdWord.Link != "" {
+				p.writeLink(height, mdWord, sty)
+			} else {
+				p.pdf.Write(height, mdWord.Text)
+			}
+			if gapWidth > 0 && strings.HasSuffix(mdWord.Text, " ") {
+				p.pdf.SetX(p.pdf.GetX() + gapWidth)
+			}
 		}
 		p.pdf.Ln(height)
 	}
-	p.pdf.SetTextColor(int(p.currStyles.Color.Text.R), int(p.currStyles.Color.Text.G), int(p.currStyles.Color.Text.B))
-	p.applyFont(p.currStyles.Font)
+	p.resetStyles()
+}
+
+// visibleLines returns the lines, which fit above the overflow bottom, when written at the current position.
+// With overflow ellipsis the last visible line ends with an ellipsis, if lines are omitted.
+func (p *Processor) visibleLines(lines []textLine, lineHeight, width float64, sty style.Styles) []textLine {
+	y := p.pdf.GetY()
+	for i, line := range lines {
+		if !line.occupied() {
+			continue
+		}
+		if y+lineHeight <= p.overflowBottom+0.001 {
+			y += lineHeight
+			continue
+		}
+		visible := lines[:i]
+		if sty.Wrap.Overflow == style.OverflowEllipsis {
+			for k := len(visible) - 1; k >= 0; k-- {
+				if len(visible[k].mdWords) > 0 {
+					visible[k] = p.ellipsisLine(visible[k], width, sty)
+					break
+				}
+			}
+		}
+		return visible
+	}
+	return lines
 }
 
-func (p *Processor) textHeight(text string, width float64, lineHeight float64, fnt style.Font) float64 {
-	p.applyFont(fnt)
-	text = p.normalizedText(text)
+// ellipsisLine shortens the line to end with an ellipsis within width.
+func (p *Processor) ellipsisLine(line textLine, width float64, sty style.Styles) textLine {
+	enc := p.encoder(sty.Font)
+	mdWords := append(markdown.Items{}, line.mdWords...)
+	lineWidth := func() float64 {
+		w := float64(0)
+		for _, mdWord := range mdWords {
+			p.applyMarkdownFont(mdWord, sty.Font)
+			w += p.pdf.GetStringWidth(enc(mdWord.Text))
+		}
+		return w
+	}
+	for len(mdWords) > 0 {
+		last := &mdWords[len(mdWords)-1]
+		last.Text = strings.TrimRight(last.Text, " ")
+		p.applyMarkdownFont(*last, sty.Font)
+		if lineWidth()+p.pdf.GetStringWidth(enc("…")) <= width {
+			last.Text += "…"
+			break
+		}
+		rs := []rune(last.Text)
+		if len(rs) <= 1 {
+			mdWords = mdWords[:len(mdWords)-1]
+			continue
+		}
+		last.Text = string(rs[:len(rs)-1])
+	}
+	line.mdWords = mdWords
+	line.textWidth = lineWidth()
+	line.textWidthTrimmedRight = line.textWidth
+	return line
+}
+
+func (p *Processor) textHeight(text string, width float64, sty style.Styles) float64 {
+	return p.itemsHeight(p.textItems(text), width, sty)
+}
+
+func (p *Processor) itemsHeight(mdWords markdown.Items, width float64, sty style.Styles) float64 {
+	p.applyFont(sty.Font)
 	_, fontHeight := p.pdf.GetFontSize()
-	height := fontHeight * lineHeight
-	mdWords := markdown.NewProcessor().Process(text).WordItems(p.transformText)
-	lines := p.textLines(mdWords, width, fnt)
+	height := fontHeight * sty.Dimension.LineHeight
+	lines := p.textLines(mdWords, width, sty)
 	textHeight := float64(0)
 	for _, line := range lines {
 		if len(line.mdWords) == 0 {
@@ -146,3 +390,31 @@ func (p *Processor) textHeight(text string, width float64, lineHeight float64, f
 	p.applyFont(p.currStyles.Font)
 	return textHeight
 }
+
+// textWidths returns the width of the widest word and the width of the longest line without wrapping.
+func (p *Processor) textWidths(text string, fnt style.Font) (float64, float64) {
+	return p.itemsWidths(p.textItems(text), fnt)
+}
+
+func (p *Processor) itemsWidths(mdWords markdown.Items, fnt style.Font) (float64, float64) {
+	enc := p.encoder(fnt)
+	min, max := float64(0), float64(0)
+	lineWidth := float64(0)
+	for _, mdWord := range mdWords {
+		if mdWord.Newline {
+			lineWidth = 0
+			continue
+		}
+		p.applyMarkdownFont(mdWord, fnt)
+		wordWidth := p.pdf.GetStringWidth(enc(strings.TrimSpace(mdWord.Text)))
+		if wordWidth > min {
+			min = wordWidth
+		}
+		if lineWidth+wordWidth > max {
+			max = lineWidth + wordWidth
+		}
+		lineWidth += p.pdf.GetStringWidth(enc(mdWord.Text))
+	}
+	p.applyFont(p.currStyles.Font)
+	return min, max
+}
@@ -0,0 +1,200 @@
+package gompdf
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+func TestTextLinesWithoutWidth(t *testing.T) {
+	p := loadTestProcessor(t, `<document><body/></document>`)
+	for _, width := range []float64{0, -5} {
+		lines := p.textLines(p.textItems("hello wonderful world"), width, DefaultStyle)
+		if n := nonEmptyLines(lines); n != 3 {
+			t.Errorf("width %v: want 3 lines, got %d", width, n)
+		}
+		for _, line := range lines {
+			if len(line.mdWords) != 1 {
+				t.Errorf("width %v: want one word per line, got %v", width, line.mdWords)
+			}
+		}
+	}
+}
+
+func TestBreakWord(t *testing.T) {
+	p := loadTestProcessor(t, `<document><body/></document>`)
+	sty := DefaultStyle
+	p.applyFont(sty.Font)
+	charWidth := p.pdf.GetStringWidth("m")
+
+	tests := []struct {
+		word        string
+		width       float64
+		firstOnLine bool
+		head, tail  string
+	}{
+		{word: "", width: 10, firstOnLine: true, head: "", tail: ""},
+		{word: "  ", width: 10, firstOnLine: true, head: "", tail: "  "},
+		{word: "mmmm", width: 0, firstOnLine: true, head: "m", tail: "mmm"},
+		{word: "mmmm", width: 2.5 * charWidth, firstOnLine: true, head: "mm", tail: "mm"},
+		{word: "mmmm", width: 2.5 * charWidth, firstOnLine: false, head: "", tail: "mmmm"},
+		{word: "mm-mm ", width: 3.5 * charWidth, firstOnLine: false, head: "mm-", tail: "mm "},
+	}
+	for _, test := range tests {
+		head, tail := p.breakWord(test.word, test.width, sty, test.firstOnLine)
+		if head != test.head || tail != test.tail {
+			t.Errorf("breakWord(%q, %v, %v): want (%q, %q), got (%q, %q)", test.word, test.width, test.firstOnLine,
+				test.head, test.tail, head, tail)
+		}
+	}
+}
+
+func TestWrapLinesBesideFloats(t *testing.T) {
+	p := loadTestProcessor(t, `<document><body/></document>`)
+	p.applyFont(DefaultStyle.Font)
+	wordWidth := p.pdf.GetStringWidth("w000")
+	//no space beside the float on the first line, too little for a word on the second one
+	spans := func(n int) (float64, float64, bool) {
+		switch n {
+		case 0:
+			return 0, 0, true
+		case 1:
+			return wordWidth / 2, wordWidth / 2, true
+		case 2:
+			return wordWidth, 1.5 * wordWidth, true
+		default:
+			return 0, 100, false
+		}
+	}
+	lines := p.wrapLines(p.textItems("w000 w001 w002"), spans, DefaultStyle)
+	words := []string{}
+	for _, line := range lines {
+		if line.blank {
+			words = append(words, "blank")
+			continue
+		}
+		s := ""
+		for _, w := range line.mdWords {
+			s += w.Text
+		}
+		words = append(words, s)
+	}
+	want := []string{"blank", "blank", "w000", "w001 w002"}
+	if strings.Join(words, "|") != strings.Join(want, "|") {
+		t.Errorf("want lines %q, got %q", want, words)
+	}
+	if lines[2].x != wordWidth {
+		t.Errorf("want offset %v of the line beside the float, got %v", wordWidth, lines[2].x)
+	}
+}
+
+var textPositionRx = regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td \((.*?)\)Tj`)
+
+// wordPositions returns the x positions (in pt) of the words written in the content, grouped by line from top to
+// bottom.
+func wordPositions(content string) [][]float64 {
+	lines := [][]float64{}
+	lastY := ""
+	for _, m := range textPositionRx.FindAllStringSubmatch(content, -1) {
+		if strings.TrimSpace(m[3]) == "" {
+			continue
+		}
+		x, _ := strconv.ParseFloat(m[1], 64)
+		if m[2] != lastY {
+			lines = append(lines, []float64{})
+			lastY = m[2]
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], x)
+	}
+	return lines
+}
+
+// wordSteps returns the distances between the positions of consecutive words.
+func wordSteps(xs []float64) []float64 {
+	steps := []float64{}
+	for i := 1; i < len(xs); i++ {
+		steps = append(steps, xs[i]-xs[i-1])
+	}
+	return steps
+}
+
+func TestJustify(t *testing.T) {
+	words := strings.TrimSpace(strings.Repeat("mmmm ", 20))
+	src := func(body string) string {
+		return `<document>
+<default><unit>mm</unit><format>a5</format><page-breaks>auto</page-breaks></default>
+<body>` + body + `</body>
+</document>`
+	}
+	p := loadTestProcessor(t, src(""))
+	sty := DefaultStyle
+	sty.Align.HAlign = style.HAlignJustify
+	p.applyFont(sty.Font)
+	//positions in the content are in pt
+	k := 72 / 25.4
+	natural := p.pdf.GetStringWidth("mmmm ") * k
+	lineWidth := p.textLines(p.textItems(words), p.effectiveWidth(0), sty)[0].width * k
+	wordWidth := p.pdf.GetStringWidth("mmmm") * k
+
+	stepsEqual := func(steps []float64, step float64) bool {
+		for _, s := range steps {
+			if math.Abs(s-step) > 0.02 {
+				return false
+			}
+		}
+		return true
+	}
+	assertJustified := func(name string, xs []float64) {
+		steps := wordSteps(xs)
+		if len(steps) == 0 || !stepsEqual(steps, steps[0]) || steps[0] <= natural+0.1 {
+			t.Errorf("%s: want the extra space shared between the gaps, got steps %v (natural %v)", name, steps, natural)
+			return
+		}
+		if end := xs[len(xs)-1] + wordWidth - xs[0]; math.Abs(end-lineWidth) > 0.02 {
+			t.Errorf("%s: want the line to fill the width %v, got %v", name, lineWidth, end)
+		}
+	}
+	assertNatural := func(name string, xs []float64) {
+		if !stepsEqual(wordSteps(xs), natural) {
+			t.Errorf("%s: want the words not stretched, got steps %v (natural %v)", name, wordSteps(xs), natural)
+		}
+	}
+
+	lines := wordPositions(pdfContent(t, processTestSource(t, src(`<text style="h-align: justify">`+words+`</text>`))))
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, got %v", lines)
+	}
+	assertJustified("first line", lines[0])
+	assertJustified("second line", lines[1])
+	assertNatural("last line", lines[2])
+
+	//lines ending in a forced break aren't stretched
+	lines = wordPositions(pdfContent(t, processTestSource(t, src(`<text style="h-align: justify">mmmm mmmm mmmm\ `+words+`</text>`))))
+	if len(lines) != 4 {
+		t.Fatalf("want 4 lines, got %v", lines)
+	}
+	assertNatural("forced break", lines[0])
+	assertJustified("after forced break", lines[1])
+
+	//links are stretched like the other words
+	linked := strings.Replace(words, "mmmm mmmm", "mmmm [mmmm](https://example.org)", 1)
+	lines = wordPositions(pdfContent(t, processTestSource(t, src(`<text style="h-align: justify">`+linked+`</text>`))))
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, got %v", lines)
+	}
+	assertJustified("line with link", lines[0])
+
+	//the last visible line of truncated text ends the text and isn't stretched
+	for _, overflow := range []string{"hidden", "ellipsis"} {
+		cell := `<table><tr><td style="height: 10; overflow: ` + overflow + `; h-align: justify">` + words + `</td></tr></table>`
+		lines = wordPositions(pdfContent(t, processTestSource(t, src(cell))))
+		if len(lines) != 1 {
+			t.Fatalf("%s: want 1 visible line, got %v", overflow, lines)
+		}
+		assertNatural(overflow, lines[0])
+	}
+}
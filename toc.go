@@ -0,0 +1,91 @@
+package gompdf
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+// Toc renders the table of contents, listing all instructions with a toc-level, their page numbers and dot leaders.
+// The entries link to the listed instructions.
+// Entries are indented by Indent per level, which defaults to the line height.
+type Toc struct {
+	Styled
+	XMLName xml.Name `xml:"toc"`
+	Indent  float64  `xml:"indent,attr"`
+}
+
+type tocEntry struct {
+	title string
+	level int
+	page  int
+}
+
+func tocEntriesEqual(es1, es2 []tocEntry) bool {
+	if len(es1) != len(es2) {
+		return false
+	}
+	for i := range es1 {
+		if es1[i] != es2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Processor) tocHeight(sty style.Styles) float64 {
+	return float64(len(p.toc)) * p.lineHeight(sty)
+}
+
+func (p *Processor) renderToc(toc *Toc, sty style.Styles) {
+	p.tocRendered = true
+	p.applyFont(sty.Font)
+	p.pdf.SetTextColor(int(sty.Color.Text.R), int(sty.Color.Text.G), int(sty.Color.Text.B))
+	tr := p.textTransformer(sty.Font)
+	width := p.effectiveWidth(sty.Dimension.Width)
+	height := p.lineHeight(sty)
+	indent := toc.Indent
+	if indent <= 0 {
+		indent = height
+	}
+	cMargin := p.pdf.GetCellMargin()
+	dotWidth := p.pdf.GetStringWidth(".")
+	spaceWidth := p.pdf.GetStringWidth(" ")
+	numberWidth := float64(0)
+	for _, e := range p.toc {
+		if w := p.pdf.GetStringWidth(fmt.Sprintf("%d", e.page)); w > numberWidth {
+			numberWidth = w
+		}
+	}
+	numberCellWidth := numberWidth + 2*cMargin
+
+	xLeft := p.pdf.GetX()
+	for i, e := range p.toc {
+		link := p.linkID(tocAnchor(i))
+		x := xLeft + float64(e.level-1)*indent
+		titleCellWidth := xLeft + width - numberCellWidth - x
+		title := p.truncatedText(tr(e.title), titleCellWidth-2*cMargin-spaceWidth-2*dotWidth)
+		dots := int((titleCellWidth - 2*cMargin - p.pdf.GetStringWidth(title) - spaceWidth) / dotWidth)
+		if dots > 0 {
+			title += " " + strings.Repeat(".", dots)
+		}
+		p.pdf.SetX(x)
+		p.pdf.CellFormat(titleCellWidth, height, title, "", 0, "L", false, link, "")
+		p.pdf.CellFormat(numberCellWidth, height, fmt.Sprintf("%d", e.page), "", 1, "R", false, link, "")
+	}
+	p.resetStyles()
+}
+
+// truncatedText shortens s with trailing dots to fit into width in the current font.
+func (p *Processor) truncatedText(s string, width float64) string {
+	if p.pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	rs := []rune(s)
+	for len(rs) > 0 && p.pdf.GetStringWidth(string(rs)+"...") > width {
+		rs = rs[:len(rs)-1]
+	}
+	return strings.TrimRight(string(rs), " ") + "..."
+}
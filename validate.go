@@ -0,0 +1,404 @@
+package gompdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mazzegi/gompdf/style"
+)
+
+// Diagnostic is a problem found in a document source. Line and Column start at 1, they are 0 if unknown.
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return d.Message
+	}
+	if d.Column == 0 {
+		return fmt.Sprintf("%d: %s", d.Line, d.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Message)
+}
+
+// Validate reports the problems of the source the document was loaded from. See ValidateSource. Documents without
+// source are checked for malformed colors, undefined classes and row-spans exceeding their table, without positions.
+func Validate(doc *Document) []Diagnostic {
+	if doc == nil {
+		return nil
+	}
+	if doc.source == nil {
+		return validateInstructions(doc)
+	}
+	return ValidateSource(doc.source)
+}
+
+// ValidateSource reports unknown elements and attributes, unknown style properties, invalid style values
+// (e.g. colors), undefined classes and row-spans exceeding their table in the document source. Unlike loading,
+// it doesn't stop at the first problem.
+func ValidateSource(src []byte) []Diagnostic {
+	v := &validator{
+		src:     src,
+		classes: map[string]bool{},
+	}
+	v.checkStyleSheet()
+	v.d = xml.NewDecoder(bytes.NewReader(src))
+	v.children("", nil)
+	return v.diags
+}
+
+// structureElements lists the child elements of the elements structuring the document. Instruction containers
+// accept the registered instructions, tables their rows and rows their cells.
+var structureElements = map[string][]string{
+	"":             {"document"},
+	"document":     {"meta", "default", "fonts", "style", "header", "footer", "body"},
+	"meta":         {"author", "creator", "subject"},
+	"default":      {"orientation", "unit", "format", "page-breaks", "page-margins"},
+	"page-margins": {"left", "top", "right", "bottom"},
+	"fonts":        {"font"},
+}
+
+var instructionContainers = map[string]bool{
+	"header":  true,
+	"footer":  true,
+	"body":    true,
+	"td":      true,
+	"columns": true,
+}
+
+type validator struct {
+	src     []byte
+	d       *xml.Decoder
+	classes map[string]bool
+	diags   []Diagnostic
+	failed  bool
+}
+
+type tableCheck struct {
+	rows [][]*cellCheck
+}
+
+type cellCheck struct {
+	offset  int64
+	rowSpan int
+}
+
+func (v *validator) report(offset int64, format string, args ...interface{}) {
+	if v.src == nil {
+		v.diags = append(v.diags, Diagnostic{Message: fmt.Sprintf(format, args...)})
+		return
+	}
+	line, col := lineColumn(v.src, offset)
+	v.diags = append(v.diags, Diagnostic{
+		Line:    line,
+		Column:  col,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) fail(offset int64, err error) {
+	v.failed = true
+	if serr, ok := err.(*xml.SyntaxError); ok {
+		v.diags = append(v.diags, Diagnostic{Line: serr.Line, Message: serr.Msg})
+		return
+	}
+	v.report(offset, "%v", err)
+}
+
+// checkStyleSheet reports the problems of the document's style sheet and collects the declared classes.
+func (v *validator) checkStyleSheet() {
+	type segment struct {
+		pos    int
+		offset int64
+	}
+	text := ""
+	segments := []segment{}
+	d := xml.NewDecoder(bytes.NewReader(v.src))
+	stack := []string{}
+	for {
+		offset := d.InputOffset()
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 2 && stack[0] == "document" && stack[1] == "style" {
+				segments = append(segments, segment{pos: len(text), offset: offset})
+				text += string(t)
+			}
+		}
+	}
+	names, problems := style.CheckClasses(text)
+	for _, n := range names {
+		v.classes[n] = true
+	}
+	for _, p := range problems {
+		seg := segments[0]
+		for _, s := range segments {
+			if s.pos <= p.Offset {
+				seg = s
+			}
+		}
+		v.report(seg.offset+int64(p.Offset-seg.pos), "style sheet: %s", p.Message)
+	}
+}
+
+// children checks the child elements of parent up to its end.
+func (v *validator) children(parent string, table *tableCheck) {
+	for !v.failed {
+		offset := v.d.InputOffset()
+		token, err := v.d.Token()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			v.fail(offset, err)
+			return
+		}
+		switch t := token.(type) {
+		case xml.EndElement:
+			return
+		case xml.StartElement:
+			if !v.allowed(parent, t.Name.Local) {
+				if parent == "" {
+					v.report(offset, "unexpected root element <%s>", t.Name.Local)
+				} else {
+					v.report(offset, "unexpected element <%s> in <%s>", t.Name.Local, parent)
+				}
+				err := v.d.Skip()
+				if err != nil {
+					v.fail(offset, err)
+				}
+				continue
+			}
+			v.element(t, offset, parent, table)
+		}
+	}
+}
+
+func (v *validator) allowed(parent, name string) bool {
+	if names, ok := structureElements[parent]; ok {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case parent == "table":
+		return name == "tr"
+	case parent == "tr":
+		return name == "td"
+	case parent == "text":
+		return name == "a"
+	case instructionContainers[parent]:
+		_, ok := instructionRegistry.types[name]
+		return ok && name != "tr" && name != "td"
+	default:
+		return false
+	}
+}
+
+func (v *validator) element(start xml.StartElement, offset int64, parent string, table *tableCheck) {
+	name := start.Name.Local
+	v.checkAttrs(start, offset, parent)
+	switch name {
+	case "table":
+		tc := &tableCheck{}
+		v.children(name, tc)
+		v.checkRowSpans(tc)
+	case "tr":
+		table.rows = append(table.rows, []*cellCheck{})
+		v.children(name, table)
+	case "td":
+		//like processTableSpans, only the cell's own style is considered
+		cell := &TableCell{}
+		cell.DecodeAttrs(start.Attr)
+		var sty style.Styles
+		cell.Apply(style.Classes{}, &sty)
+		ir := len(table.rows) - 1
+		table.rows[ir] = append(table.rows[ir], &cellCheck{offset: offset, rowSpan: sty.Table.RowSpan})
+		v.children(name, nil)
+	default:
+		v.children(name, nil)
+	}
+}
+
+func (v *validator) checkAttrs(start xml.StartElement, offset int64, parent string) {
+	name := start.Name.Local
+	known := map[string]bool{}
+	if name == "font" && parent == "fonts" {
+		known = xmlAttrs(reflect.TypeOf(FontSource{}))
+	} else if _, ok := instructionRegistry.types[name]; instructionContainers[name] && !ok {
+		known = xmlAttrs(reflect.TypeOf(Instructions{}))
+	} else if proto, ok := instructionRegistry.types[name]; ok && structureElements[parent] == nil {
+		known = xmlAttrs(reflect.TypeOf(proto).Elem())
+	}
+	for _, a := range start.Attr {
+		if a.Name.Space != "" {
+			continue
+		}
+		if !known[a.Name.Local] {
+			v.report(offset, "unknown attribute (%s) of <%s>", a.Name.Local, name)
+			continue
+		}
+		switch a.Name.Local {
+		case "style":
+			for _, p := range style.Check(a.Value) {
+				v.report(offset, "style of <%s>: %s", name, p.Message)
+			}
+		case "class":
+			for _, c := range strings.Fields(a.Value) {
+				if !v.classes[c] {
+					v.report(offset, "undefined class (%s) of <%s>", c, name)
+				}
+			}
+		}
+	}
+}
+
+// validateInstructions checks the decoded instructions of a document without source.
+func validateInstructions(doc *Document) []Diagnostic {
+	v := &validator{
+		classes: map[string]bool{},
+	}
+	for name := range doc.styleClasses {
+		v.classes[name] = true
+	}
+	for _, is := range []Instructions{doc.Header, doc.Footer, doc.Body} {
+		v.instructions(is.iss)
+	}
+	for _, err := range doc.styleProblems() {
+		v.report(0, "%v", err)
+	}
+	return v.diags
+}
+
+func (v *validator) instructions(is []Instruction) {
+	for _, i := range is {
+		v.checkClasses(i)
+		switch i := i.(type) {
+		case *Columns:
+			v.instructions(i.Instructions.iss)
+		case *Table:
+			v.table(i)
+		}
+	}
+}
+
+func (v *validator) table(t *Table) {
+	tc := &tableCheck{}
+	for _, row := range t.Rows {
+		v.checkClasses(row)
+		cells := []*cellCheck{}
+		for _, cell := range row.Cells {
+			if cell.spannedBy != nil {
+				//inserted by processing the table's spans
+				continue
+			}
+			v.checkClasses(cell)
+			var sty style.Styles
+			cell.Apply(style.Classes{}, &sty)
+			cells = append(cells, &cellCheck{rowSpan: sty.Table.RowSpan})
+			v.instructions(cell.Instructions)
+		}
+		tc.rows = append(tc.rows, cells)
+	}
+	v.checkRowSpans(tc)
+}
+
+// checkClasses reports the undefined classes of an instruction.
+func (v *validator) checkClasses(i Instruction) {
+	s, ok := i.(interface{ classNames() []string })
+	if !ok {
+		return
+	}
+	for _, c := range s.classNames() {
+		if !v.classes[c] {
+			v.report(0, "undefined class (%s) of <%s>", c, xmlName(i))
+		}
+	}
+}
+
+// xmlName returns the element name of an instruction.
+func xmlName(i Instruction) string {
+	f, ok := reflect.TypeOf(i).Elem().FieldByName("XMLName")
+	if !ok {
+		return ""
+	}
+	return f.Tag.Get("xml")
+}
+
+// checkRowSpans mirrors processTableSpans, which inserts the cells spanned by a row-span into the following rows.
+func (v *validator) checkRowSpans(tc *tableCheck) {
+	for ir := range tc.rows {
+		for ic, cell := range tc.rows[ir] {
+			if cell.offset < 0 {
+				continue
+			}
+			if cell.rowSpan < 0 {
+				v.report(cell.offset, "invalid row-span (%d)", cell.rowSpan)
+				continue
+			}
+			for n := 1; n < cell.rowSpan; n++ {
+				if ir+n >= len(tc.rows) {
+					v.report(cell.offset, "row-span (%d) exceeds the table by %d rows", cell.rowSpan, ir+cell.rowSpan-len(tc.rows))
+					break
+				}
+				spannedRow := tc.rows[ir+n]
+				if ic > len(spannedRow) {
+					v.report(cell.offset, "row-span (%d) reaches row %d, which has only %d cells", cell.rowSpan, ir+n+1, len(spannedRow))
+					break
+				}
+				cells := append([]*cellCheck{}, spannedRow[:ic]...)
+				cells = append(cells, &cellCheck{offset: -1})
+				tc.rows[ir+n] = append(cells, spannedRow[ic:]...)
+			}
+		}
+	}
+}
+
+// xmlAttrs returns the names of the attributes decoded into struct type t, including the attributes of
+// embedded structs. Styled types decode style and class.
+func xmlAttrs(t reflect.Type) map[string]bool {
+	attrs := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if f.Type == reflect.TypeOf(Styled{}) {
+				attrs["style"] = true
+				attrs["class"] = true
+			}
+			for a := range xmlAttrs(f.Type) {
+				attrs[a] = true
+			}
+			continue
+		}
+		if tag := f.Tag.Get("xml"); strings.HasSuffix(tag, ",attr") {
+			attrs[strings.TrimSuffix(tag, ",attr")] = true
+		}
+	}
+	return attrs
+}
+
+// lineColumn returns the line and column (in characters) of offset in b.
+func lineColumn(b []byte, offset int64) (int, int) {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+	lineStart := bytes.LastIndexByte(b[:offset], '\n') + 1
+	return lineAt(b, offset), utf8.RuneCount(b[lineStart:offset]) + 1
+}
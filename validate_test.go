@@ -0,0 +1,80 @@
+package gompdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidateWithoutSource(t *testing.T) {
+	doc, err := Load(bytes.NewBufferString(`<document>
+<style>known { font-point-size: 10 }</style>
+<body>
+<text class="known unknown">a</text>
+<columns count="2"><box class="other">b</box></columns>
+<table><tr><td style="row-span: 3">c</td></tr><tr><td>d</td></tr></table>
+</body>
+</document>`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	doc.source = nil
+	want := []string{
+		"undefined class (unknown) of <text>",
+		"undefined class (other) of <box>",
+		"row-span (3) exceeds the table by 1 rows",
+	}
+	diags := Validate(doc)
+	if len(diags) != len(want) {
+		t.Fatalf("want %d diagnostics, got %v", len(want), diags)
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Errorf("diagnostic %d: want (%s), got (%s)", i, want[i], d)
+		}
+	}
+	if err := checkStrict(doc); err == nil {
+		t.Errorf("want strict check to fail")
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	src := `<document>
+<style>
+known { font-point-size: 10 }
+broken { font-point-size: ten }
+</style>
+<body>
+<text class="known" style="color: #12345">a</text>
+<box colour="red">b</box>
+<unknown/>
+<text class="missing">c</text>
+<table>
+<tr><td style="row-span: 3">d</td></tr>
+<tr><td>e</td></tr>
+</table>
+</body>
+</document>`
+	want := []string{
+		"4:",
+		"7:1: style of <text>",
+		"8:1: unknown attribute (colour) of <box>",
+		"9:1: unexpected element <unknown> in <body>",
+		"10:1: undefined class (missing) of <text>",
+		"12:5: row-span (3) exceeds the table by 1 rows",
+	}
+	diags := ValidateSource([]byte(src))
+	if len(diags) != len(want) {
+		t.Fatalf("want %d diagnostics, got %v", len(want), diags)
+	}
+	for i, d := range diags {
+		if !strings.HasPrefix(d.String(), want[i]) {
+			t.Errorf("diagnostic %d: want prefix (%s), got (%s)", i, want[i], d)
+		}
+	}
+
+	diags = ValidateSource([]byte("<document>\n<body>\n<text>a</body>\n</document>"))
+	if len(diags) != 1 || diags[0].Line != 3 {
+		t.Errorf("want syntax error on line 3, got %v", diags)
+	}
+}